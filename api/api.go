@@ -19,6 +19,7 @@ func New(svc service.Service, rdb *redis.Client) *Api {
 	r := gin.Default()
 	api := &Api{svc, r, rdb}
 	go SubscribeToMatchChanges(rdb, svc)
+	go ApplySeasonWalkovers(svc, rdb)
 	api.registerMiddlewares()
 	api.registerEndpoints()
 	return api
@@ -39,14 +40,62 @@ func (a *Api) registerEndpoints() {
 		})
 	})
 	a.r.GET("/api/matches", a.GetMatchInfoList)
+	a.r.GET("/api/matches/:match_id", a.GetMatchDetails)
+	a.r.GET("/api/tournaments", a.GetTournaments)
+	a.r.GET("/api/tournaments/:tournament_id/events", a.GetEvents)
+	a.r.GET("/api/events/:event_id/groups", a.GetGroups)
+	a.r.GET("/api/groups/:group_id/standings", a.GetGroupStandings)
+	a.r.GET("/api/ties/:tie_id", a.GetTie)
+	a.r.GET("/api/events/:event_id/swiss/standings", a.GetSwissStandings)
+	a.r.GET("/api/events/:event_id/bracket", a.GetBracket)
+	a.r.GET("/api/events/:event_id/bracket.svg", a.GetBracketSvg)
+	a.r.GET("/api/events/:event_id/draw", a.GetDraw)
+	a.r.GET("/api/events/:event_id/draw/commitment", a.GetDrawCommitment)
+	a.r.GET("/api/seasons", a.GetSeasons)
+	a.r.GET("/api/seasons/:season_id", a.GetSeason)
+	a.r.GET("/api/seasons/:season_id/table", a.GetLeagueTable)
+	a.r.GET("/api/players", a.GetPlayers)
+	a.r.GET("/api/players/:player_id", a.GetPlayer)
+	a.r.GET("/api/players/:player_id/ratings", a.GetPlayerRatingHistory)
+	a.r.GET("/api/rankings", a.GetRankings)
+	a.r.GET("/api/match-formats", a.GetMatchFormatTemplates)
+	a.r.GET("/api/match-formats/:name", a.GetMatchFormatTemplate)
 	a.r.GET("/ws", func(ctx *gin.Context) {
 		serveWs(ctx.Writer, ctx.Request, a.svc)
 	})
 
 	a.r.Use(adminAuthMiddleware())
+	a.r.POST("/api/matches", a.CreateMatch)
+	a.r.POST("/api/tournaments", a.CreateTournament)
+	a.r.POST("/api/tournaments/:tournament_id/events", a.CreateEvent)
+	a.r.POST("/api/events/:event_id/knockout", a.GenerateKnockoutBracket)
+	a.r.POST("/api/events/:event_id/groups", a.CreateGroup)
+	a.r.POST("/api/events/:event_id/qualify", a.QualifyFromGroups)
+	a.r.POST("/api/groups/:group_id/matches", a.AddMatchToGroup)
+	a.r.POST("/api/ties", a.CreateTie)
+	a.r.POST("/api/events/:event_id/entries", a.AddEventEntries)
+	a.r.POST("/api/events/:event_id/swiss/rounds", a.GenerateSwissRound)
+	a.r.POST("/api/events/:event_id/draw/commitment", a.CommitDrawSeed)
+	a.r.POST("/api/events/:event_id/draw", a.HoldDraw)
+	a.r.POST("/api/seasons", a.CreateSeason)
+	a.r.POST("/api/players", a.CreatePlayer)
+	a.r.PUT("/api/players/:player_id", a.UpdatePlayer)
+	a.r.DELETE("/api/players/:player_id", a.DeletePlayer)
+	a.r.POST("/api/match-formats", a.CreateMatchFormatTemplate)
+	a.r.PUT("/api/match-formats/:name", a.UpdateMatchFormatTemplate)
+	a.r.DELETE("/api/match-formats/:name", a.DeleteMatchFormatTemplate)
+	a.r.POST("/api/matches/:match_id/result", a.EndMatch)
+	a.r.POST("/api/matches/:match_id/ready", a.ReportFixtureReady)
+	a.r.POST("/api/matches/:match_id/events", a.RecordMatchEvent)
 	a.r.POST("/api/matches/:match_id/sets", a.CreateSet)
 	a.r.POST("/api/matches/:match_id/sets/:set_id/score", a.UpdateScore)
 	a.r.PATCH("/api/matches/:match_id/sets/:set_id/score", a.UndoScore)
+	a.r.PUT("/api/matches/:match_id/sets/:set_id/score", a.CorrectSetScore)
+	a.r.POST("/api/matches/:match_id/undo", a.Undo)
+	a.r.POST("/api/matches/:match_id/redo", a.Redo)
+	a.r.PUT("/api/matches/:match_id/sets/:set_id/service-order", a.SetDoublesServiceOrder)
+	a.r.POST("/api/matches/:match_id/sets/:set_id/expedite", a.StartExpedite)
+	a.r.POST("/api/matches/:match_id/sets/:set_id/expedite/return", a.AwardExpediteReturnPoint)
 }
 
 func (a *Api) Serve(addr string) error {
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+var bracketTypes = []enums.BracketType{
+	enums.MainBracket,
+	enums.ConsolationBracket,
+	enums.LosersBracket,
+	enums.GrandFinalBracket,
+}
+
+func (a *Api) GetBracket(ctx *gin.Context) {
+	eventId, bracket, tree, ok := a.bracketTree(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.BracketResponse{
+		EventId: eventId,
+		Bracket: string(bracket),
+		Rounds:  bracketDepth(tree),
+		Root:    tree,
+	})
+}
+
+// GetBracketSvg renders the bracket from the current results on every
+// request, so venue screens pick up finished matches by reloading it. The
+// ETag lets them poll without downloading an unchanged drawing.
+func (a *Api) GetBracketSvg(ctx *gin.Context) {
+	_, _, tree, ok := a.bracketTree(ctx)
+	if !ok {
+		return
+	}
+
+	svg := renderBracketSvg(tree)
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum([]byte(svg)))
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("ETag", etag)
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(svg))
+}
+
+func (a *Api) bracketTree(ctx *gin.Context) (int, enums.BracketType, *dto.BracketNodeResponse, bool) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return 0, "", nil, false
+	}
+
+	bracket := enums.BracketType(ctx.DefaultQuery("bracket", string(enums.MainBracket)))
+	valid := false
+	for _, b := range bracketTypes {
+		valid = valid || b == bracket
+	}
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid bracket"})
+		return 0, "", nil, false
+	}
+
+	tree, err := a.svc.GetBracketTree(eventId, bracket)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) || errors.Is(err, service.ErrBracketNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return 0, "", nil, false
+	}
+
+	return eventId, bracket, newBracketNodeResponse(tree), true
+}
+
+func newBracketNodeResponse(node *service.BracketNode) *dto.BracketNodeResponse {
+	if node == nil {
+		return nil
+	}
+
+	response := &dto.BracketNodeResponse{
+		MatchId:  node.MatchId,
+		Round:    node.Round,
+		Position: node.Position,
+		Stage:    node.Stage,
+		Status:   node.Status,
+		Result:   node.Result,
+	}
+	for i, slot := range node.Slots {
+		response.Slots[i] = dto.BracketSlotResponse{
+			OpponentId: slot.OpponentId,
+			Name:       slot.Name,
+			Score:      slot.Score,
+			IsWinner:   slot.IsWinner,
+			Feeder:     newBracketNodeResponse(slot.Feeder),
+		}
+	}
+	return response
+}
+
+// bracketDepth counts the rounds on the longest path to the root.
+func bracketDepth(node *dto.BracketNodeResponse) int {
+	if node == nil {
+		return 0
+	}
+	depth := 0
+	for _, slot := range node.Slots {
+		if d := bracketDepth(slot.Feeder); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+const (
+	svgMargin    = 20
+	svgBoxWidth  = 200
+	svgRowHeight = 24
+	svgColumnGap = 40
+	svgSlotGap   = 16
+)
+
+// bracketLayout places every match of a bracket drawing. Rows count slots from
+// the top, so a match whose slots sit on rows r and r+1 is centred between
+// the matches feeding it.
+type bracketLayout struct {
+	columns  int
+	nextRow  float64
+	elements []string
+}
+
+// renderBracketSvg draws the bracket with the first round on the left and the
+// root match on the right. Winners are shown in bold.
+func renderBracketSvg(root *dto.BracketNodeResponse) string {
+	layout := &bracketLayout{columns: bracketDepth(root)}
+	layout.place(root, 0)
+
+	rows := layout.nextRow
+	width := 2*svgMargin + layout.columns*svgBoxWidth + (layout.columns-1)*svgColumnGap
+	height := 2*svgMargin + int(rows*(2*svgRowHeight+svgSlotGap)/2)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="13">`,
+		width, height, width, height)
+	b.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>`)
+	for _, element := range layout.elements {
+		b.WriteString(element)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// place draws the match and everything feeding it, and returns the y of the
+// middle of the match box.
+func (l *bracketLayout) place(node *dto.BracketNodeResponse, depth int) float64 {
+	x := float64(svgMargin + (l.columns-1-depth)*(svgBoxWidth+svgColumnGap))
+
+	var slotCentres [2]float64
+	var feederCentres [2]*float64
+	for i, slot := range node.Slots {
+		if slot.Feeder != nil {
+			centre := l.place(slot.Feeder, depth+1)
+			slotCentres[i] = centre
+			feederCentres[i] = &centre
+		} else {
+			slotCentres[i] = l.rowCentre(l.nextRow)
+			l.nextRow += 1
+		}
+	}
+
+	middle := (slotCentres[0] + slotCentres[1]) / 2
+	top := middle - svgRowHeight
+
+	for i, centre := range feederCentres {
+		if centre == nil {
+			continue
+		}
+		slotY := top + float64(i)*svgRowHeight + svgRowHeight/2
+		fromX := x - svgColumnGap
+		l.elements = append(l.elements, fmt.Sprintf(
+			`<path d="M%.1f %.1f H%.1f V%.1f H%.1f" fill="none" stroke="#888888"/>`,
+			fromX, *centre, fromX+svgColumnGap/2, slotY, x,
+		))
+	}
+
+	fill := "#f4f4f4"
+	if node.Status == string(enums.Ongoing) {
+		fill = "#fff4d6"
+	}
+	l.elements = append(l.elements, fmt.Sprintf(
+		`<rect x="%.1f" y="%.1f" width="%d" height="%d" fill="%s" stroke="#444444"/>`,
+		x, top, svgBoxWidth, 2*svgRowHeight, fill,
+	))
+	l.elements = append(l.elements, fmt.Sprintf(
+		`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#cccccc"/>`,
+		x, middle, x+svgBoxWidth, middle,
+	))
+
+	for i, slot := range node.Slots {
+		baseline := top + float64(i)*svgRowHeight + svgRowHeight*0.7
+		name := slot.Name
+		if name == "" {
+			name = "TBD"
+		}
+		weight := "normal"
+		if slot.IsWinner {
+			weight = "bold"
+		}
+		l.elements = append(l.elements, fmt.Sprintf(
+			`<text x="%.1f" y="%.1f" font-weight="%s">%s</text>`,
+			x+6, baseline, weight, html.EscapeString(truncateName(name, 24)),
+		))
+		if node.Status != string(enums.Upcoming) {
+			l.elements = append(l.elements, fmt.Sprintf(
+				`<text x="%.1f" y="%.1f" text-anchor="end" font-weight="%s">%d</text>`,
+				x+svgBoxWidth-6, baseline, weight, slot.Score,
+			))
+		}
+	}
+
+	return middle
+}
+
+func (l *bracketLayout) rowCentre(row float64) float64 {
+	return svgMargin + row*(2*svgRowHeight+svgSlotGap)/2 + svgRowHeight/2
+}
+
+func truncateName(name string, length int) string {
+	runes := []rune(name)
+	if len(runes) <= length {
+		return name
+	}
+	return string(runes[:length-1]) + "…"
+}
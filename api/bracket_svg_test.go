@@ -0,0 +1,60 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestRenderBracketSvg(t *testing.T) {
+	semiFinal := &dto.BracketNodeResponse{
+		MatchId: 2,
+		Status:  string(enums.Past),
+		Slots: [2]dto.BracketSlotResponse{
+			{Name: "Bo", Score: 1},
+			{Name: "Cai <Jr>", Score: 3, IsWinner: true},
+		},
+	}
+	root := &dto.BracketNodeResponse{
+		MatchId: 1,
+		Status:  string(enums.Upcoming),
+		Slots: [2]dto.BracketSlotResponse{
+			{Name: "Ana"},
+			{Name: "Cai <Jr>", Feeder: semiFinal},
+		},
+	}
+
+	if depth := bracketDepth(root); depth != 2 {
+		t.Errorf("bracketDepth() = %d, want 2", depth)
+	}
+
+	svg := renderBracketSvg(root)
+	for _, want := range []string{
+		`width="480" height="`,
+		`font-weight="bold">Cai &lt;Jr&gt;</text>`,
+		`text-anchor="end" font-weight="bold">3</text>`,
+		`<path d="M`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg does not contain %q", want)
+		}
+	}
+	if strings.Count(svg, "<rect x=") != 2 {
+		t.Errorf("svg draws %d matches, want 2", strings.Count(svg, "<rect x="))
+	}
+	// The final is upcoming, so only the semi final shows scores.
+	if strings.Count(svg, `text-anchor="end"`) != 2 {
+		t.Errorf("svg shows %d scores, want 2", strings.Count(svg, `text-anchor="end"`))
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	if got := truncateName("Ana", 5); got != "Ana" {
+		t.Errorf("truncateName() = %q, want %q", got, "Ana")
+	}
+	if got := truncateName("Åsa Lindqvist", 5); got != "Åsa …" {
+		t.Errorf("truncateName() = %q, want %q", got, "Åsa …")
+	}
+}
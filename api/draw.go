@@ -0,0 +1,180 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) HoldDraw(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.HoldDrawRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	entries := make([]service.BracketEntry, 0, len(requestBody.Entries))
+	for _, e := range requestBody.Entries {
+		entries = append(entries, service.BracketEntry{OpponentId: e.OpponentId, Seed: e.Seed})
+	}
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	drawType := enums.SingleElimination
+	if requestBody.DrawType != "" {
+		drawType = enums.DrawType(requestBody.DrawType)
+	}
+
+	d, err := a.svc.HoldDraw(eventId, entries, requestBody.RandomSeed, drawType, settings)
+	if err != nil {
+		if errors.Is(err, service.ErrDrawSeedNotCommitted) || errors.Is(err, service.ErrDrawSeedMismatch) ||
+			errors.Is(err, service.ErrDrawSeedNotRevealed) || errors.Is(err, service.ErrDrawEntriesNotRegistered) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	response := dto.DrawResponse{
+		Id:         d.Id,
+		EventId:    d.EventId,
+		RandomSeed: d.RandomSeed,
+		DrawType:   string(d.DrawType),
+		Shuffle:    string(d.Shuffle),
+		CreatedAt:  d.CreatedAt,
+		Entries:    make([]dto.DrawEntryResponse, 0, len(d.Entries)),
+	}
+	for _, e := range d.Entries {
+		response.Entries = append(response.Entries, dto.DrawEntryResponse{OpponentId: e.OpponentId, Seed: e.Seed, Slot: e.Slot})
+	}
+	if c := d.Commitment; c != nil {
+		response.Commitment = &dto.DrawCommitmentResponse{
+			EventId:     c.EventId,
+			SeedHash:    c.SeedHash,
+			CommittedBy: c.CommittedBy,
+			CreatedAt:   c.CreatedAt,
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (a *Api) GetDraw(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	d, err := a.svc.GetDraw(eventId)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) || errors.Is(err, service.ErrDrawNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	response := dto.DrawResponse{
+		Id:         d.Id,
+		EventId:    d.EventId,
+		RandomSeed: d.RandomSeed,
+		DrawType:   string(d.DrawType),
+		Shuffle:    string(d.Shuffle),
+		CreatedAt:  d.CreatedAt,
+		Entries:    make([]dto.DrawEntryResponse, 0, len(d.Entries)),
+	}
+	for _, e := range d.Entries {
+		response.Entries = append(response.Entries, dto.DrawEntryResponse{OpponentId: e.OpponentId, Seed: e.Seed, Slot: e.Slot})
+	}
+	if c := d.Commitment; c != nil {
+		response.Commitment = &dto.DrawCommitmentResponse{
+			EventId:     c.EventId,
+			SeedHash:    c.SeedHash,
+			CommittedBy: c.CommittedBy,
+			CreatedAt:   c.CreatedAt,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (a *Api) CommitDrawSeed(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.CommitDrawSeedRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	c, err := a.svc.CommitDrawSeed(eventId, requestBody.SeedHash, requestBody.CommittedBy)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrDrawSeedAlreadyCommitted) || errors.Is(err, service.ErrBracketAlreadyGenerated) ||
+			errors.Is(err, service.ErrDrawSeedAfterEntries) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrInvalidSeedHash) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.DrawCommitmentResponse{
+		EventId:     c.EventId,
+		SeedHash:    c.SeedHash,
+		CommittedBy: c.CommittedBy,
+		CreatedAt:   c.CreatedAt,
+	})
+}
+
+func (a *Api) GetDrawCommitment(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	c, err := a.svc.GetDrawCommitment(eventId)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) || errors.Is(err, service.ErrDrawCommitmentNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.DrawCommitmentResponse{
+		EventId:     c.EventId,
+		SeedHash:    c.SeedHash,
+		CommittedBy: c.CommittedBy,
+		CreatedAt:   c.CreatedAt,
+	})
+}
@@ -0,0 +1,26 @@
+package dto
+
+type BracketSlotResponse struct {
+	OpponentId int                  `json:"opponent_id"`
+	Name       string               `json:"name"`
+	Score      int                  `json:"score"`
+	IsWinner   bool                 `json:"is_winner"`
+	Feeder     *BracketNodeResponse `json:"feeder"`
+}
+
+type BracketNodeResponse struct {
+	MatchId  int                    `json:"match_id"`
+	Round    int                    `json:"round"`
+	Position int                    `json:"position"`
+	Stage    string                 `json:"stage"`
+	Status   string                 `json:"status"`
+	Result   string                 `json:"result"`
+	Slots    [2]BracketSlotResponse `json:"slots"`
+}
+
+type BracketResponse struct {
+	EventId int                  `json:"event_id"`
+	Bracket string               `json:"bracket"`
+	Rounds  int                  `json:"rounds"`
+	Root    *BracketNodeResponse `json:"root"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type HoldDrawRequest struct {
+	Entries        []BracketEntryRequest `json:"entries" binding:"required,dive"`
+	RandomSeed     *int64                `json:"random_seed"`
+	DrawType       string                `json:"draw_type" binding:"omitempty,oneof=SINGLE_ELIMINATION CONSOLATION DOUBLE_ELIMINATION"`
+	FormatTemplate string                `json:"format_template"`
+	MaxSets        int                   `json:"max_sets"`
+	GamePoint      int                   `json:"game_point"`
+	ScoringRules   string                `json:"scoring_rules"`
+}
+
+type CommitDrawSeedRequest struct {
+	SeedHash    string `json:"seed_hash" binding:"required"`
+	CommittedBy string `json:"committed_by" binding:"required"`
+}
+
+type DrawCommitmentResponse struct {
+	EventId     int       `json:"event_id"`
+	SeedHash    string    `json:"seed_hash"`
+	CommittedBy string    `json:"committed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DrawEntryResponse struct {
+	OpponentId int `json:"opponent_id"`
+	Seed       int `json:"seed"`
+	Slot       int `json:"slot"`
+}
+
+type DrawResponse struct {
+	Id         int                     `json:"id"`
+	EventId    int                     `json:"event_id"`
+	RandomSeed int64                   `json:"random_seed"`
+	DrawType   string                  `json:"draw_type"`
+	Shuffle    string                  `json:"shuffle"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Entries    []DrawEntryResponse     `json:"entries"`
+	Commitment *DrawCommitmentResponse `json:"commitment"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+type CreateGroupRequest struct {
+	Name           string `json:"name" binding:"required"`
+	OpponentIds    []int  `json:"opponent_ids" binding:"required"`
+	FormatTemplate string `json:"format_template"`
+	MaxSets        int    `json:"max_sets"`
+	GamePoint      int    `json:"game_point"`
+	ScoringRules   string `json:"scoring_rules"`
+}
+
+type GroupResponse struct {
+	Id          int    `json:"id"`
+	EventId     int    `json:"event_id"`
+	Name        string `json:"name"`
+	OpponentIds []int  `json:"opponent_ids"`
+}
+
+type StandingResponse struct {
+	Position    int    `json:"position"`
+	OpponentId  int    `json:"opponent_id"`
+	Name        string `json:"name"`
+	Played      int    `json:"played"`
+	Won         int    `json:"won"`
+	Lost        int    `json:"lost"`
+	MatchPoints int    `json:"match_points"`
+	GamesWon    int    `json:"games_won"`
+	GamesLost   int    `json:"games_lost"`
+	PointsWon   int    `json:"points_won"`
+	PointsLost  int    `json:"points_lost"`
+}
+
+type AddMatchToGroupRequest struct {
+	MatchId int `json:"match_id" binding:"required"`
+}
+
+type QualifyFromGroupsRequest struct {
+	QualifiersPerGroup int    `json:"qualifiers_per_group" binding:"required"`
+	FormatTemplate     string `json:"format_template"`
+	MaxSets            int    `json:"max_sets"`
+	GamePoint          int    `json:"game_point"`
+	ScoringRules       string `json:"scoring_rules"`
+}
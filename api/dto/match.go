@@ -1,21 +1,50 @@
 package dto
 
+import "time"
+
 type OpponentResponse struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	IsWinner bool   `json:"is_winner"`
+	Id       int              `json:"id"`
+	Name     string           `json:"name"`
+	IsWinner bool             `json:"is_winner"`
+	Players  []PlayerResponse `json:"players"`
 }
 
 type MatchInfoResponse struct {
-	Id        int                `json:"id"`
-	Format    string             `json:"format"`
-	Stage     string             `json:"stage"`
-	Status    string             `json:"status"`
-	Opponents []OpponentResponse `json:"opponents"`
+	Id           int                `json:"id"`
+	EventId      *int               `json:"event_id"`
+	TieId        *int               `json:"tie_id"`
+	RubberNumber *int               `json:"rubber_number"`
+	SeasonId     *int               `json:"season_id"`
+	MatchWeek    *int               `json:"match_week"`
+	Deadline     *time.Time         `json:"deadline"`
+	Format       string             `json:"format"`
+	Stage        string             `json:"stage"`
+	Status       string             `json:"status"`
+	Result       string             `json:"result"`
+	Opponents    []OpponentResponse `json:"opponents"`
+}
+
+type CreateMatchRequest struct {
+	Format         string `json:"format" binding:"oneof=SINGLES DOUBLES"`
+	Stage          string `json:"stage" binding:"oneof=PRELIMS KNOCKOUT QUARTER_FINAL SEMI_FINAL FINAL"`
+	OppAId         int    `json:"opp_a_id" binding:"required"`
+	OppBId         int    `json:"opp_b_id" binding:"required"`
+	EventId        *int   `json:"event_id"`
+	FormatTemplate string `json:"format_template"`
+	MaxSets        int    `json:"max_sets"`
+	GamePoint      int    `json:"game_point"`
+	ScoringRules   string `json:"scoring_rules"`
+	HandicapA      int    `json:"handicap_a"`
+	HandicapB      int    `json:"handicap_b"`
 }
 
+// MatchSubscribeRequest subscribes to a single match, or to every match of a
+// tournament, event or tie when MatchId is not given.
 type MatchSubscribeRequest struct {
-	MatchId int `json:"match_id"`
+	MatchId      int  `json:"match_id"`
+	TournamentId *int `json:"tournament_id"`
+	EventId      *int `json:"event_id"`
+	TieId        *int `json:"tie_id"`
 }
 
 type SetResponse struct {
@@ -24,26 +53,68 @@ type SetResponse struct {
 	OpponentAScore int              `json:"opp_a_score"`
 	OpponentBScore int              `json:"opp_b_score"`
 	IsCompleted    bool             `json:"is_completed"`
+	Version        int              `json:"version"`
+	FirstServerIsA bool             `json:"first_server_is_a"`
+	IsExpedited    bool             `json:"is_expedited"`
+	StartedAt      time.Time        `json:"started_at"`
 	Logs           []SetLogResponse `json:"logs"`
 }
 
 type SetLogResponse struct {
-	Id        int  `json:"id"`
-	OppAScore int  `json:"opp_a_score"`
-	OppBScore int  `json:"opp_b_score"`
-	ScoredByA bool `json:"scored_by_a"`
+	Id        int       `json:"id"`
+	OppAScore int       `json:"opp_a_score"`
+	OppBScore int       `json:"opp_b_score"`
+	ScoredByA bool      `json:"scored_by_a"`
+	ServedByA bool      `json:"served_by_a"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MatchEventResponse struct {
+	Id        int       `json:"id"`
+	SetId     *int      `json:"set_id"`
+	Type      string    `json:"type"`
+	IsOppA    *bool     `json:"is_opp_a"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TimelineEntryResponse struct {
+	Type      string              `json:"type"`
+	SetNumber int                 `json:"set_number"`
+	Time      time.Time           `json:"time"`
+	Point     *SetLogResponse     `json:"point,omitempty"`
+	Event     *MatchEventResponse `json:"event,omitempty"`
 }
 
 type MatchDetail struct {
-	Id        int                `json:"id"`
-	Format    string             `json:"format"`
-	Stage     string             `json:"stage"`
-	Status    string             `json:"status"`
-	Opponents []OpponentResponse `json:"opponents"`
-	Sets      []SetResponse      `json:"sets"`
+	Id             int                     `json:"id"`
+	TournamentId   *int                    `json:"tournament_id"`
+	EventId        *int                    `json:"event_id"`
+	GroupId        *int                    `json:"group_id"`
+	Tie            *TieResponse            `json:"tie"`
+	RubberNumber   *int                    `json:"rubber_number"`
+	Bracket        string                  `json:"bracket"`
+	Format         string                  `json:"format"`
+	Stage          string                  `json:"stage"`
+	Status         string                  `json:"status"`
+	Result         string                  `json:"result"`
+	FormatTemplate string                  `json:"format_template"`
+	SetCount       int                     `json:"set_count"`
+	GamePoint      int                     `json:"game_point"`
+	ScoringRules   string                  `json:"scoring_rules"`
+	HandicapA      int                     `json:"handicap_a"`
+	HandicapB      int                     `json:"handicap_b"`
+	Opponents      []OpponentResponse      `json:"opponents"`
+	Sets           []SetResponse           `json:"sets"`
+	ServerIsA      *bool                   `json:"server_is_a"`
+	ServerName     string                  `json:"server_name"`
+	ReceiverName   string                  `json:"receiver_name"`
+	IsExpedited    bool                    `json:"is_expedited"`
+	Events         []MatchEventResponse    `json:"events"`
+	Timeline       []TimelineEntryResponse `json:"timeline"`
 }
 
 type MatchDetailResponse struct {
 	Data  MatchDetail `json:"data"`
-	Error string          `json:"error"`
+	Error string      `json:"error"`
 }
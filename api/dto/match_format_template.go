@@ -0,0 +1,15 @@
+package dto
+
+type MatchFormatTemplateRequest struct {
+	Name         string `json:"name"`
+	SetCount     int    `json:"set_count" binding:"required"`
+	GamePoint    int    `json:"game_point" binding:"required"`
+	ScoringRules string `json:"scoring_rules"`
+}
+
+type MatchFormatTemplateResponse struct {
+	Name         string `json:"name"`
+	SetCount     int    `json:"set_count"`
+	GamePoint    int    `json:"game_point"`
+	ScoringRules string `json:"scoring_rules"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type PlayerRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Club       string `json:"club"`
+	Country    string `json:"country"`
+	Handedness string `json:"handedness"`
+	GripStyle  string `json:"grip_style"`
+	BirthYear  *int   `json:"birth_year"`
+	Gender     string `json:"gender"`
+	PhotoUrl   string `json:"photo_url"`
+}
+
+type CreatePlayerRequest struct {
+	PlayerRequest
+	TournamentId *int `json:"tournament_id"`
+}
+
+type PlayerResponse struct {
+	Id           int    `json:"id"`
+	TournamentId *int   `json:"tournament_id"`
+	Name         string `json:"name"`
+	Club         string `json:"club"`
+	Country      string `json:"country"`
+	Handedness   string `json:"handedness"`
+	GripStyle    string `json:"grip_style"`
+	BirthYear    *int   `json:"birth_year"`
+	Gender       string `json:"gender"`
+	PhotoUrl     string `json:"photo_url"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type RankingResponse struct {
+	Position     int     `json:"position"`
+	PlayerIds    []int   `json:"player_ids"`
+	Name         string  `json:"name"`
+	Rating       float64 `json:"rating"`
+	MatchesRated int     `json:"matches_rated"`
+}
+
+type RatingChangeResponse struct {
+	MatchId      int       `json:"match_id"`
+	RatingBefore float64   `json:"rating_before"`
+	RatingAfter  float64   `json:"rating_after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type CreateSeasonRequest struct {
+	Name           string    `json:"name" binding:"required"`
+	Format         string    `json:"format" binding:"oneof=SINGLES DOUBLES"`
+	StartsAt       time.Time `json:"starts_at" binding:"required"`
+	WeekDays       int       `json:"week_days"`
+	WalkoverRule   string    `json:"walkover_rule" binding:"omitempty,oneof=HOME HIGHER_PLACED"`
+	OpponentIds    []int     `json:"opponent_ids" binding:"required"`
+	FormatTemplate string    `json:"format_template"`
+	MaxSets        int       `json:"max_sets"`
+	GamePoint      int       `json:"game_point"`
+	ScoringRules   string    `json:"scoring_rules"`
+}
+
+type SeasonResponse struct {
+	Id           int       `json:"id"`
+	Name         string    `json:"name"`
+	Format       string    `json:"format"`
+	StartsAt     time.Time `json:"starts_at"`
+	WeekDays     int       `json:"week_days"`
+	WalkoverRule string    `json:"walkover_rule"`
+	OpponentIds  []int     `json:"opponent_ids"`
+}
+
+type SeasonDetailResponse struct {
+	SeasonResponse
+	Fixtures []MatchInfoResponse `json:"fixtures"`
+}
+
+type ReportFixtureReadyRequest struct {
+	OpponentId int `json:"opponent_id" binding:"required"`
+}
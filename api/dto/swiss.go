@@ -0,0 +1,29 @@
+package dto
+
+type AddEventEntriesRequest struct {
+	Entries []BracketEntryRequest `json:"entries" binding:"required,dive"`
+}
+
+type GenerateSwissRoundRequest struct {
+	FormatTemplate string `json:"format_template"`
+	MaxSets        int    `json:"max_sets"`
+	GamePoint      int    `json:"game_point"`
+	ScoringRules   string `json:"scoring_rules"`
+}
+
+type SwissRoundResponse struct {
+	EventId int `json:"event_id"`
+	Round   int `json:"round"`
+}
+
+type SwissStandingResponse struct {
+	Position   int    `json:"position"`
+	OpponentId int    `json:"opponent_id"`
+	Name       string `json:"name"`
+	Played     int    `json:"played"`
+	Won        int    `json:"won"`
+	Lost       int    `json:"lost"`
+	Byes       int    `json:"byes"`
+	Score      int    `json:"score"`
+	Buchholz   int    `json:"buchholz"`
+}
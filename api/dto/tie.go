@@ -0,0 +1,32 @@
+package dto
+
+type CreateTieRequest struct {
+	EventId        *int           `json:"event_id"`
+	Stage          string         `json:"stage" binding:"omitempty,oneof=PRELIMS KNOCKOUT QUARTER_FINAL SEMI_FINAL FINAL"`
+	SideA          string         `json:"side_a" binding:"required"`
+	SideB          string         `json:"side_b" binding:"required"`
+	Order          []string       `json:"order" binding:"required"`
+	LineupA        map[string]int `json:"lineup_a" binding:"required"`
+	LineupB        map[string]int `json:"lineup_b" binding:"required"`
+	FormatTemplate string         `json:"format_template"`
+	MaxSets        int            `json:"max_sets"`
+	GamePoint      int            `json:"game_point"`
+	ScoringRules   string         `json:"scoring_rules"`
+}
+
+type TieResponse struct {
+	Id           int    `json:"id"`
+	EventId      *int   `json:"event_id"`
+	SideA        string `json:"side_a"`
+	SideB        string `json:"side_b"`
+	RubbersToWin int    `json:"rubbers_to_win"`
+	ScoreA       int    `json:"score_a"`
+	ScoreB       int    `json:"score_b"`
+	Status       string `json:"status"`
+	WinnerIsA    *bool  `json:"winner_is_a"`
+}
+
+type TieDetailResponse struct {
+	TieResponse
+	Rubbers []MatchInfoResponse `json:"rubbers"`
+}
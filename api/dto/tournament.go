@@ -0,0 +1,36 @@
+package dto
+
+type CreateTournamentRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TournamentResponse struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateEventRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Format string `json:"format" binding:"oneof=SINGLES DOUBLES TEAM"`
+}
+
+type EventResponse struct {
+	Id           int    `json:"id"`
+	TournamentId int    `json:"tournament_id"`
+	Name         string `json:"name"`
+	Format       string `json:"format"`
+}
+
+type BracketEntryRequest struct {
+	OpponentId int `json:"opponent_id" binding:"required"`
+	Seed       int `json:"seed"`
+}
+
+type GenerateKnockoutBracketRequest struct {
+	Entries        []BracketEntryRequest `json:"entries" binding:"required,dive"`
+	DrawType       string                `json:"draw_type" binding:"omitempty,oneof=SINGLE_ELIMINATION CONSOLATION DOUBLE_ELIMINATION"`
+	FormatTemplate string                `json:"format_template"`
+	MaxSets        int                   `json:"max_sets"`
+	GamePoint      int                   `json:"game_point"`
+	ScoringRules   string                `json:"scoring_rules"`
+}
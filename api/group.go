@@ -0,0 +1,177 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) CreateGroup(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.CreateGroupRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	id, err := a.svc.CreateGroup(eventId, requestBody.Name, requestBody.OpponentIds, settings)
+	if err != nil {
+		abortWithBracketError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.GroupResponse{
+		Id:          id,
+		EventId:     eventId,
+		Name:        requestBody.Name,
+		OpponentIds: requestBody.OpponentIds,
+	})
+}
+
+func (a *Api) GetGroups(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	groups, err := a.svc.GetGroups(eventId)
+	if err != nil {
+		abortWithTournamentError(ctx, err)
+		return
+	}
+
+	response := make([]dto.GroupResponse, 0, len(groups))
+	for _, g := range groups {
+		response = append(response, dto.GroupResponse{
+			Id:          g.Id,
+			EventId:     g.EventId,
+			Name:        g.Name,
+			OpponentIds: g.OpponentIds,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"groups": response})
+}
+
+func (a *Api) GetGroupStandings(ctx *gin.Context) {
+	groupId, err := strconv.Atoi(ctx.Params.ByName("group_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	standings, err := a.svc.GetGroupStandings(groupId)
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	response := make([]dto.StandingResponse, 0, len(standings))
+	for _, st := range standings {
+		response = append(response, dto.StandingResponse{
+			Position:    st.Position,
+			OpponentId:  st.OpponentId,
+			Name:        st.Name,
+			Played:      st.Played,
+			Won:         st.Won,
+			Lost:        st.Lost,
+			MatchPoints: st.MatchPoints,
+			GamesWon:    st.GamesWon,
+			GamesLost:   st.GamesLost,
+			PointsWon:   st.PointsWon,
+			PointsLost:  st.PointsLost,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"standings": response})
+}
+
+func (a *Api) AddMatchToGroup(ctx *gin.Context) {
+	groupId, err := strconv.Atoi(ctx.Params.ByName("group_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.AddMatchToGroupRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := a.svc.AddMatchToGroup(groupId, requestBody.MatchId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrGroupNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrNotPrelimsMatch) || errors.Is(err, service.ErrMatchNotInEvent) ||
+			errors.Is(err, service.ErrMatchInAnotherGroup) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	go PublishMatchChange(requestBody.MatchId, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) QualifyFromGroups(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.QualifyFromGroupsRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	err = a.svc.QualifyFromGroups(eventId, requestBody.QualifiersPerGroup, settings)
+	if err != nil {
+		if errors.Is(err, service.ErrNoGroups) || errors.Is(err, service.ErrGroupNotFinished) ||
+			errors.Is(err, service.ErrInvalidQualifierCount) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) GenerateKnockoutBracket(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.GenerateKnockoutBracketRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	entries := make([]service.BracketEntry, 0, len(requestBody.Entries))
+	for _, e := range requestBody.Entries {
+		entries = append(entries, service.BracketEntry{OpponentId: e.OpponentId, Seed: e.Seed})
+	}
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	drawType := enums.SingleElimination
+	if requestBody.DrawType != "" {
+		drawType = enums.DrawType(requestBody.DrawType)
+	}
+
+	if err := a.svc.GenerateKnockoutBracket(eventId, entries, drawType, settings); err != nil {
+		abortWithBracketError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func abortWithBracketError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrEventNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrBracketAlreadyGenerated) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrNotEnoughEntries) || errors.Is(err, service.ErrDuplicateEntry) ||
+		errors.Is(err, service.ErrDuplicateSeed) || errors.Is(err, service.ErrOpponentNotInTournament) ||
+		errors.Is(err, service.ErrUnknownScoringRules) || errors.Is(err, service.ErrInvalidSetCount) ||
+		errors.Is(err, service.ErrInvalidGamePoint) || errors.Is(err, service.ErrMatchFormatTemplateNotFound) ||
+		errors.Is(err, service.ErrMatchFormatTemplateConflict) ||
+		errors.Is(err, service.ErrTeamEventNeedsTies) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,7 +22,14 @@ func (a *Api) GetMatchInfoList(ctx *gin.Context) {
 		return
 	}
 
-	matchInfoList, err := a.svc.GetMatchInfoList(queryParams.Filter)
+	tournamentId, err1 := optionalIntQuery(ctx, "tournament_id")
+	eventId, err2 := optionalIntQuery(ctx, "event_id")
+	if err1 != nil || err2 != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	matchInfoList, err := a.svc.GetMatchInfoList(queryParams.Filter, tournamentId, eventId)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -31,14 +43,22 @@ func (a *Api) GetMatchInfoList(ctx *gin.Context) {
 				Id:       opp.Id,
 				Name:     opp.Name,
 				IsWinner: opp.IsWinner,
+				Players:  newPlayerResponses(opp.Players),
 			}
 		}
 		matchInfo = append(matchInfo, dto.MatchInfoResponse{
-			Id:        mi.Id,
-			Format:    string(mi.Format),
-			Status:    string(mi.Status),
-			Stage:     string(mi.Stage),
-			Opponents: opponents,
+			Id:           mi.Id,
+			EventId:      mi.EventId,
+			TieId:        mi.TieId,
+			RubberNumber: mi.RubberNumber,
+			SeasonId:     mi.SeasonId,
+			MatchWeek:    mi.MatchWeek,
+			Deadline:     mi.Deadline,
+			Format:       string(mi.Format),
+			Status:       string(mi.Status),
+			Stage:        string(mi.Stage),
+			Result:       string(mi.Result),
+			Opponents:    opponents,
 		})
 	}
 
@@ -46,3 +66,114 @@ func (a *Api) GetMatchInfoList(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+func (a *Api) CreateMatch(ctx *gin.Context) {
+	var requestBody dto.CreateMatchRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings := service.MatchSettings{
+		EventId:        requestBody.EventId,
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+		HandicapA:      requestBody.HandicapA,
+		HandicapB:      requestBody.HandicapB,
+	}
+	stage := enums.MatchStage(requestBody.Stage)
+
+	var err error
+	if enums.MatchFormat(requestBody.Format) == enums.Doubles {
+		err = a.svc.CreateDoublesMatch(stage, requestBody.OppAId, requestBody.OppBId, settings)
+	} else {
+		err = a.svc.CreateSinglesMatch(stage, requestBody.OppAId, requestBody.OppBId, settings)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownScoringRules) || errors.Is(err, service.ErrInvalidHandicap) ||
+			errors.Is(err, service.ErrInvalidSetCount) || errors.Is(err, service.ErrInvalidGamePoint) ||
+			errors.Is(err, service.ErrMatchFormatTemplateNotFound) || errors.Is(err, service.ErrEventNotFound) ||
+			errors.Is(err, service.ErrMatchFormatTemplateConflict) ||
+			errors.Is(err, service.ErrEventFormatMismatch) || errors.Is(err, service.ErrOpponentNotInTournament) ||
+			errors.Is(err, service.ErrTeamEventNeedsTies) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func (a *Api) EndMatch(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody struct {
+		Result    string `json:"result" binding:"oneof=WALKOVER RETIRED DISQUALIFIED"`
+		WinnerIsA *bool  `json:"winner_is_a" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	changed, err := a.svc.EndMatch(matchId, enums.MatchResult(requestBody.Result), *requestBody.WinnerIsA)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrInvalidMatchResult) || errors.Is(err, service.ErrMatchAlreadyCompleted) || errors.Is(err, service.ErrMatchAlreadyStarted) ||
+			errors.Is(err, service.ErrOpponentsNotDecided) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) GetMatchDetails(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	md, err := a.svc.GetMatchDetails(matchId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewMatchDetailsResponse(md))
+}
+
+// optionalIntQuery reads an integer query parameter, returning nil when it is
+// not given.
+func optionalIntQuery(ctx *gin.Context, key string) (*int, error) {
+	value, ok := ctx.GetQuery(key)
+	if !ok || value == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) RecordMatchEvent(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody struct {
+		Type   string `json:"type" binding:"required"`
+		IsOppA *bool  `json:"is_opp_a"`
+		Detail string `json:"detail"`
+	}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err = a.svc.RecordMatchEvent(matchId, enums.MatchEventType(requestBody.Type), requestBody.IsOppA, requestBody.Detail)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrInvalidMatchEvent) || errors.Is(err, service.ErrOpponentRequired) ||
+			errors.Is(err, service.ErrNoSetInProgress) || errors.Is(err, service.ErrMatchAlreadyCompleted) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrTimeoutAlreadyTaken) || errors.Is(err, service.ErrYellowCardAlreadyShown) ||
+			errors.Is(err, service.ErrYellowCardRequired) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	go PublishMatchChange(matchId, a.rdb)
+
+	ctx.Status(http.StatusCreated)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) GetMatchFormatTemplates(ctx *gin.Context) {
+	templates, err := a.svc.GetMatchFormatTemplates()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.MatchFormatTemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		response = append(response, dto.MatchFormatTemplateResponse{
+			Name:         t.Name,
+			SetCount:     t.SetCount,
+			GamePoint:    t.GamePoint,
+			ScoringRules: string(t.ScoringRules),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"match_formats": response})
+}
+
+func (a *Api) GetMatchFormatTemplate(ctx *gin.Context) {
+	t, err := a.svc.GetMatchFormatTemplate(ctx.Params.ByName("name"))
+	if err != nil {
+		abortWithMatchFormatTemplateError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.MatchFormatTemplateResponse{
+		Name:         t.Name,
+		SetCount:     t.SetCount,
+		GamePoint:    t.GamePoint,
+		ScoringRules: string(t.ScoringRules),
+	})
+}
+
+func (a *Api) CreateMatchFormatTemplate(ctx *gin.Context) {
+	var requestBody dto.MatchFormatTemplateRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := a.svc.CreateMatchFormatTemplate(
+		requestBody.Name,
+		requestBody.SetCount,
+		requestBody.GamePoint,
+		scoringRulesOrDefault(requestBody.ScoringRules),
+	)
+	if err != nil {
+		abortWithMatchFormatTemplateError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func (a *Api) UpdateMatchFormatTemplate(ctx *gin.Context) {
+	var requestBody dto.MatchFormatTemplateRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := a.svc.UpdateMatchFormatTemplate(
+		ctx.Params.ByName("name"),
+		requestBody.SetCount,
+		requestBody.GamePoint,
+		scoringRulesOrDefault(requestBody.ScoringRules),
+	)
+	if err != nil {
+		abortWithMatchFormatTemplateError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) DeleteMatchFormatTemplate(ctx *gin.Context) {
+	if err := a.svc.DeleteMatchFormatTemplate(ctx.Params.ByName("name")); err != nil {
+		abortWithMatchFormatTemplateError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func scoringRulesOrDefault(scoringRules string) enums.ScoringRuleSet {
+	if scoringRules == "" {
+		return enums.StandardRules
+	}
+	return enums.ScoringRuleSet(scoringRules)
+}
+
+func abortWithMatchFormatTemplateError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrMatchFormatTemplateNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrMatchFormatTemplateExists) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrInvalidSetCount) || errors.Is(err, service.ErrInvalidGamePoint) ||
+		errors.Is(err, service.ErrUnknownScoringRules) || errors.Is(err, service.ErrMatchFormatTemplateNameRequired) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) GetPlayers(ctx *gin.Context) {
+	tournamentId, err := optionalIntQuery(ctx, "tournament_id")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	players, err := a.svc.GetPlayers(ctx.Query("name"), tournamentId)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"players": newPlayerResponses(players)})
+}
+
+func (a *Api) GetPlayer(ctx *gin.Context) {
+	playerId, err := strconv.Atoi(ctx.Params.ByName("player_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	player, err := a.svc.GetPlayer(playerId)
+	if err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPlayerResponse(*player))
+}
+
+func (a *Api) CreatePlayer(ctx *gin.Context) {
+	var requestBody dto.CreatePlayerRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	id, err := a.svc.CreatePlayer(newPlayerProfile(requestBody.PlayerRequest), requestBody.TournamentId)
+	if err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	player, err := a.svc.GetPlayer(id)
+	if err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newPlayerResponse(*player))
+}
+
+func (a *Api) UpdatePlayer(ctx *gin.Context) {
+	playerId, err := strconv.Atoi(ctx.Params.ByName("player_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.PlayerRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := a.svc.UpdatePlayer(playerId, newPlayerProfile(requestBody)); err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) DeletePlayer(ctx *gin.Context) {
+	playerId, err := strconv.Atoi(ctx.Params.ByName("player_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	if err := a.svc.DeletePlayer(playerId); err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func newPlayerProfile(request dto.PlayerRequest) service.PlayerProfile {
+	return service.PlayerProfile{
+		Name:       request.Name,
+		Club:       request.Club,
+		Country:    request.Country,
+		Handedness: enums.Handedness(request.Handedness),
+		GripStyle:  enums.GripStyle(request.GripStyle),
+		BirthYear:  request.BirthYear,
+		Gender:     enums.Gender(request.Gender),
+		PhotoUrl:   request.PhotoUrl,
+	}
+}
+
+func newPlayerResponse(p service.Player) dto.PlayerResponse {
+	return dto.PlayerResponse{
+		Id:           p.Id,
+		TournamentId: p.TournamentId,
+		Name:         p.Name,
+		Club:         p.Club,
+		Country:      p.Country,
+		Handedness:   string(p.Handedness),
+		GripStyle:    string(p.GripStyle),
+		BirthYear:    p.BirthYear,
+		Gender:       string(p.Gender),
+		PhotoUrl:     p.PhotoUrl,
+	}
+}
+
+func newPlayerResponses(players []service.Player) []dto.PlayerResponse {
+	response := make([]dto.PlayerResponse, 0, len(players))
+	for _, p := range players {
+		response = append(response, newPlayerResponse(p))
+	}
+	return response
+}
+
+func abortWithPlayerError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrPlayerNotFound) || errors.Is(err, service.ErrTournamentNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrPlayerInUse) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrPlayerNameRequired) || errors.Is(err, service.ErrInvalidHandedness) ||
+		errors.Is(err, service.ErrInvalidGripStyle) || errors.Is(err, service.ErrInvalidGender) ||
+		errors.Is(err, service.ErrInvalidBirthYear) || errors.Is(err, service.ErrInvalidPhotoUrl) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRankings lists the Elo rankings of players for SINGLES or of pairs of
+// players for DOUBLES. Undoing a result takes its rating change back out but
+// keeps the changes of every match rated since, so after results are undone
+// and redone the ratings can drift slightly from the ratings the remaining
+// results would give if rated again in order.
+func (a *Api) GetRankings(ctx *gin.Context) {
+	var queryParams struct {
+		Format string `form:"format" binding:"omitempty,oneof=SINGLES DOUBLES"`
+	}
+
+	if err := ctx.ShouldBindQuery(&queryParams); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid format, choices are SINGLES & DOUBLES"})
+		return
+	}
+
+	format := enums.Singles
+	if queryParams.Format != "" {
+		format = enums.MatchFormat(queryParams.Format)
+	}
+
+	rankings, err := a.svc.GetRankings(format)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.RankingResponse, 0, len(rankings))
+	for _, r := range rankings {
+		response = append(response, dto.RankingResponse{
+			Position:     r.Position,
+			PlayerIds:    r.PlayerIds,
+			Name:         r.Name,
+			Rating:       r.Rating,
+			MatchesRated: r.MatchesRated,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"format": format, "rankings": response})
+}
+
+func (a *Api) GetPlayerRatingHistory(ctx *gin.Context) {
+	playerId, err := strconv.Atoi(ctx.Params.ByName("player_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	history, err := a.svc.GetPlayerRatingHistory(playerId)
+	if err != nil {
+		abortWithPlayerError(ctx, err)
+		return
+	}
+
+	response := make([]dto.RatingChangeResponse, 0, len(history))
+	for _, c := range history {
+		response = append(response, dto.RatingChangeResponse{
+			MatchId:      c.MatchId,
+			RatingBefore: c.RatingBefore,
+			RatingAfter:  c.RatingAfter,
+			CreatedAt:    c.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"player_id": playerId, "ratings": response})
+}
@@ -18,6 +18,17 @@ func PublishMatchChange(matchId int, rdb *redis.Client) {
 	}
 }
 
+// PublishMatchChanges notifies the subscribers of the match and of the other
+// matches the change reached, such as the next match of a bracket.
+func PublishMatchChanges(matchId int, changedMatchIds []int, rdb *redis.Client) {
+	PublishMatchChange(matchId, rdb)
+	for _, id := range changedMatchIds {
+		if id != matchId {
+			PublishMatchChange(id, rdb)
+		}
+	}
+}
+
 func SubscribeToMatchChanges(rdb *redis.Client, svc service.Service) {
 	pubsub := rdb.Subscribe(context.Background(), channel)
 
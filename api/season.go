@@ -0,0 +1,242 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const seasonWalkoverInterval = 15 * time.Minute
+
+func (a *Api) CreateSeason(ctx *gin.Context) {
+	var requestBody dto.CreateSeasonRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	weekDays := requestBody.WeekDays
+	if weekDays == 0 {
+		weekDays = 7
+	}
+	walkoverRule := enums.HomeWins
+	if requestBody.WalkoverRule != "" {
+		walkoverRule = enums.WalkoverRule(requestBody.WalkoverRule)
+	}
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	id, err := a.svc.CreateSeason(
+		requestBody.Name,
+		enums.MatchFormat(requestBody.Format),
+		requestBody.StartsAt,
+		weekDays,
+		walkoverRule,
+		requestBody.OpponentIds,
+		settings,
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWeekLength) || errors.Is(err, service.ErrInvalidWalkoverRule) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SeasonResponse{
+		Id:           id,
+		Name:         requestBody.Name,
+		Format:       requestBody.Format,
+		StartsAt:     requestBody.StartsAt,
+		WeekDays:     weekDays,
+		WalkoverRule: string(walkoverRule),
+		OpponentIds:  requestBody.OpponentIds,
+	})
+}
+
+func (a *Api) GetSeasons(ctx *gin.Context) {
+	seasons, err := a.svc.GetSeasons()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.SeasonResponse, 0, len(seasons))
+	for _, s := range seasons {
+		response = append(response, dto.SeasonResponse{
+			Id:           s.Id,
+			Name:         s.Name,
+			Format:       string(s.Format),
+			StartsAt:     s.StartsAt,
+			WeekDays:     s.WeekDays,
+			WalkoverRule: string(s.WalkoverRule),
+			OpponentIds:  s.OpponentIds,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"seasons": response})
+}
+
+func (a *Api) GetSeason(ctx *gin.Context) {
+	seasonId, err := strconv.Atoi(ctx.Params.ByName("season_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	s, err := a.svc.GetSeason(seasonId)
+	if err != nil {
+		abortWithSeasonError(ctx, err)
+		return
+	}
+	fixtures, err := a.svc.GetSeasonFixtures(seasonId)
+	if err != nil {
+		abortWithSeasonError(ctx, err)
+		return
+	}
+
+	response := dto.SeasonDetailResponse{
+		SeasonResponse: dto.SeasonResponse{
+			Id:           s.Id,
+			Name:         s.Name,
+			Format:       string(s.Format),
+			StartsAt:     s.StartsAt,
+			WeekDays:     s.WeekDays,
+			WalkoverRule: string(s.WalkoverRule),
+			OpponentIds:  s.OpponentIds,
+		},
+		Fixtures: make([]dto.MatchInfoResponse, 0, len(fixtures)),
+	}
+	for _, mi := range fixtures {
+		opponents := make([]dto.OpponentResponse, 2)
+		for i, opp := range mi.Opponents {
+			opponents[i] = dto.OpponentResponse{
+				Id:       opp.Id,
+				Name:     opp.Name,
+				IsWinner: opp.IsWinner,
+				Players:  newPlayerResponses(opp.Players),
+			}
+		}
+		response.Fixtures = append(response.Fixtures, dto.MatchInfoResponse{
+			Id:           mi.Id,
+			EventId:      mi.EventId,
+			TieId:        mi.TieId,
+			RubberNumber: mi.RubberNumber,
+			SeasonId:     mi.SeasonId,
+			MatchWeek:    mi.MatchWeek,
+			Deadline:     mi.Deadline,
+			Format:       string(mi.Format),
+			Status:       string(mi.Status),
+			Stage:        string(mi.Stage),
+			Result:       string(mi.Result),
+			Opponents:    opponents,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (a *Api) GetLeagueTable(ctx *gin.Context) {
+	seasonId, err := strconv.Atoi(ctx.Params.ByName("season_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	standings, err := a.svc.GetLeagueTable(seasonId)
+	if err != nil {
+		abortWithSeasonError(ctx, err)
+		return
+	}
+
+	response := make([]dto.StandingResponse, 0, len(standings))
+	for _, st := range standings {
+		response = append(response, dto.StandingResponse{
+			Position:    st.Position,
+			OpponentId:  st.OpponentId,
+			Name:        st.Name,
+			Played:      st.Played,
+			Won:         st.Won,
+			Lost:        st.Lost,
+			MatchPoints: st.MatchPoints,
+			GamesWon:    st.GamesWon,
+			GamesLost:   st.GamesLost,
+			PointsWon:   st.PointsWon,
+			PointsLost:  st.PointsLost,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"table": response})
+}
+
+func (a *Api) ReportFixtureReady(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.ReportFixtureReadyRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err = a.svc.ReportFixtureReady(matchId, requestBody.OpponentId, time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrNotSeasonFixture) || errors.Is(err, service.ErrOpponentNotInMatch) ||
+			errors.Is(err, service.ErrMatchAlreadyCompleted) || errors.Is(err, service.ErrMatchAlreadyStarted) ||
+			errors.Is(err, service.ErrFixtureDeadlinePassed) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// ApplySeasonWalkovers walks over the overdue season fixtures at a fixed
+// interval and notifies the subscribers of every fixture it closes.
+func ApplySeasonWalkovers(svc service.Service, rdb *redis.Client) {
+	ticker := time.NewTicker(seasonWalkoverInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		matchIds, err := svc.ApplySeasonWalkovers(now)
+		if err != nil {
+			log.Println("Failed to apply season walkovers:", err)
+			continue
+		}
+		for _, matchId := range matchIds {
+			PublishMatchChange(matchId, rdb)
+		}
+	}
+}
+
+func abortWithSeasonError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrSeasonNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
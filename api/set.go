@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -16,10 +17,22 @@ func (a *Api) CreateSet(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 	}
 
-	if err := a.svc.CreateSet(id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	var requestBody struct {
+		FirstServerIsA *bool `json:"first_server_is_a"`
+	}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil && !errors.Is(err, io.EOF) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := a.svc.CreateSet(id, requestBody.FirstServerIsA); err != nil {
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, sql.ErrNoRows) {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrPreviousSetNotCompleted) {
+		} else if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrPreviousSetNotCompleted) ||
+			errors.Is(err, service.ErrOpponentsNotDecided) {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -41,7 +54,9 @@ func (a *Api) UpdateScore(ctx *gin.Context) {
 	}
 
 	var requestBody struct {
-		ScoredByA *bool `json:"scored_by_a" binding:"required"`
+		ScoredByA   *bool `json:"scored_by_a" binding:"required"`
+		AutoNextSet bool  `json:"auto_next_set"`
+		Version     *int  `json:"version"`
 	}
 
 	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
@@ -49,8 +64,11 @@ func (a *Api) UpdateScore(ctx *gin.Context) {
 		return
 	}
 
-	if err := a.svc.UpdateScore(matchId, setId, *requestBody.ScoredByA); err != nil {
-		if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrPreviousSetNotCompleted) || errors.Is(err, service.ErrSetAlreadyCompleted) {
+	changed, err := a.svc.UpdateScore(matchId, setId, *requestBody.ScoredByA, requestBody.AutoNextSet, requestBody.Version)
+	if err != nil {
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrPreviousSetNotCompleted) || errors.Is(err, service.ErrSetAlreadyCompleted) {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -58,7 +76,7 @@ func (a *Api) UpdateScore(ctx *gin.Context) {
 		return
 	}
 
-	go PublishMatchChange(matchId, a.rdb)
+	go PublishMatchChanges(matchId, changed, a.rdb)
 
 	ctx.Status(http.StatusAccepted)
 }
@@ -71,8 +89,11 @@ func (a *Api) UndoScore(ctx *gin.Context) {
 		return
 	}
 
-	if err := a.svc.UndoScoreUpdate(matchId, setId); err != nil {
-		if errors.Is(err, service.ErrNoScoreToUndo) {
+	changed, err := a.svc.UndoScoreUpdate(matchId, setId)
+	if err != nil {
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrNoScoreToUndo) || errors.Is(err, service.ErrUndoPastCorrection) {
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else if errors.Is(err, service.ErrSetNotFound) {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -82,7 +103,187 @@ func (a *Api) UndoScore(ctx *gin.Context) {
 		return
 	}
 
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) CorrectSetScore(ctx *gin.Context) {
+	matchId, err1 := strconv.Atoi(ctx.Params.ByName("match_id"))
+	setId, err2 := strconv.Atoi(ctx.Params.ByName("set_id"))
+	if err1 != nil || err2 != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody struct {
+		OppAScore *int   `json:"opp_a_score" binding:"required"`
+		OppBScore *int   `json:"opp_b_score" binding:"required"`
+		Reason    string `json:"reason" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	changed, err := a.svc.CorrectSetScore(matchId, setId, *requestBody.OppAScore, *requestBody.OppBScore, requestBody.Reason)
+	if err != nil {
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrSetNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrCorrectionReasonRequired) || errors.Is(err, service.ErrInvalidCorrection) ||
+			errors.Is(err, service.ErrNextMatchStarted) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) Undo(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	changed, err := a.svc.Undo(matchId)
+	if err != nil {
+		abortWithScoreHistoryError(ctx, err)
+		return
+	}
+
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) Redo(ctx *gin.Context) {
+	matchId, err := strconv.Atoi(ctx.Params.ByName("match_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	changed, err := a.svc.Redo(matchId)
+	if err != nil {
+		abortWithScoreHistoryError(ctx, err)
+		return
+	}
+
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func abortWithScoreHistoryError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrConcurrentUpdate) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if errors.Is(err, sql.ErrNoRows) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrNoScoreToUndo) || errors.Is(err, service.ErrNoScoreToRedo) ||
+		errors.Is(err, service.ErrUndoPastCorrection) ||
+		errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrSetAlreadyCompleted) ||
+		errors.Is(err, service.ErrPreviousSetNotCompleted) || errors.Is(err, service.ErrNextMatchStarted) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (a *Api) SetDoublesServiceOrder(ctx *gin.Context) {
+	matchId, err1 := strconv.Atoi(ctx.Params.ByName("match_id"))
+	setId, err2 := strconv.Atoi(ctx.Params.ByName("set_id"))
+	if err1 != nil || err2 != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody struct {
+		FirstServerIsPlayerA   *bool `json:"first_server_is_player_a" binding:"required"`
+		FirstReceiverIsPlayerA *bool `json:"first_receiver_is_player_a"`
+	}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err := a.svc.SetDoublesServiceOrder(matchId, setId, *requestBody.FirstServerIsPlayerA, requestBody.FirstReceiverIsPlayerA)
+	if err != nil {
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrSetNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrSetAlreadyCompleted) ||
+			errors.Is(err, service.ErrNotDoublesMatch) || errors.Is(err, service.ErrSetAlreadyStarted) ||
+			errors.Is(err, service.ErrFirstReceiverRequired) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
 	go PublishMatchChange(matchId, a.rdb)
 
 	ctx.Status(http.StatusAccepted)
 }
+
+func (a *Api) StartExpedite(ctx *gin.Context) {
+	matchId, err1 := strconv.Atoi(ctx.Params.ByName("match_id"))
+	setId, err2 := strconv.Atoi(ctx.Params.ByName("set_id"))
+	if err1 != nil || err2 != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	if err := a.svc.StartExpedite(matchId, setId); err != nil {
+		abortWithExpediteError(ctx, err)
+		return
+	}
+
+	go PublishMatchChange(matchId, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *Api) AwardExpediteReturnPoint(ctx *gin.Context) {
+	matchId, err1 := strconv.Atoi(ctx.Params.ByName("match_id"))
+	setId, err2 := strconv.Atoi(ctx.Params.ByName("set_id"))
+	if err1 != nil || err2 != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	changed, err := a.svc.AwardExpediteReturnPoint(matchId, setId)
+	if err != nil {
+		abortWithExpediteError(ctx, err)
+		return
+	}
+
+	go PublishMatchChanges(matchId, changed, a.rdb)
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func abortWithExpediteError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrConcurrentUpdate) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrSetNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if errors.Is(err, service.ErrGameOverOrSetCountExceeded) || errors.Is(err, service.ErrSetAlreadyCompleted) ||
+		errors.Is(err, service.ErrExpediteAlreadyStarted) || errors.Is(err, service.ErrExpediteNotStarted) ||
+		errors.Is(err, service.ErrExpediteTooLate) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
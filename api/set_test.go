@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+// conflictingService fails every score update as if the set had been scored
+// by another request in the meantime.
+type conflictingService struct {
+	service.Service
+}
+
+func (conflictingService) UpdateScore(matchId int, setId int, scoredByA bool, autoNextSet bool, expectedVersion *int) ([]int, error) {
+	return nil, service.ErrConcurrentUpdate
+}
+
+func TestUpdateScoreConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a := &Api{svc: conflictingService{}, r: gin.New()}
+	a.r.POST("/api/matches/:match_id/sets/:set_id/score", a.UpdateScore)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/matches/1/sets/2/score", strings.NewReader(`{"scored_by_a": true, "version": 3}`))
+	recorder := httptest.NewRecorder()
+	a.r.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) AddEventEntries(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.AddEventEntriesRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	entries := make([]service.BracketEntry, 0, len(requestBody.Entries))
+	for _, e := range requestBody.Entries {
+		entries = append(entries, service.BracketEntry{OpponentId: e.OpponentId, Seed: e.Seed})
+	}
+
+	if err := a.svc.AddEventEntries(eventId, entries); err != nil {
+		if errors.Is(err, service.ErrPlayerNotFound) || errors.Is(err, service.ErrTeamNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func (a *Api) GenerateSwissRound(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.GenerateSwissRoundRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	round, err := a.svc.GenerateSwissRound(eventId, settings)
+	if err != nil {
+		if errors.Is(err, service.ErrSwissRoundNotFinished) || errors.Is(err, service.ErrNoSwissPairing) ||
+			errors.Is(err, service.ErrSwissRoundsExhausted) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.SwissRoundResponse{EventId: eventId, Round: round})
+}
+
+func (a *Api) GetSwissStandings(ctx *gin.Context) {
+	eventId, err := strconv.Atoi(ctx.Params.ByName("event_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	standings, err := a.svc.GetSwissStandings(eventId)
+	if err != nil {
+		abortWithTournamentError(ctx, err)
+		return
+	}
+
+	response := make([]dto.SwissStandingResponse, 0, len(standings))
+	for _, st := range standings {
+		response = append(response, dto.SwissStandingResponse{
+			Position:   st.Position,
+			OpponentId: st.OpponentId,
+			Name:       st.Name,
+			Played:     st.Played,
+			Won:        st.Won,
+			Lost:       st.Lost,
+			Byes:       st.Byes,
+			Score:      st.Score,
+			Buchholz:   st.Buchholz,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"standings": response})
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) CreateTie(ctx *gin.Context) {
+	var requestBody dto.CreateTieRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	settings := service.MatchSettings{
+		FormatTemplate: requestBody.FormatTemplate,
+		MaxSets:        requestBody.MaxSets,
+		GamePoint:      requestBody.GamePoint,
+		ScoringRules:   enums.ScoringRuleSet(requestBody.ScoringRules),
+	}
+
+	stage := enums.Prelims
+	if requestBody.Stage != "" {
+		stage = enums.MatchStage(requestBody.Stage)
+	}
+
+	id, err := a.svc.CreateTie(
+		requestBody.EventId,
+		stage,
+		requestBody.SideA,
+		requestBody.SideB,
+		requestBody.Order,
+		requestBody.LineupA,
+		requestBody.LineupB,
+		settings,
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTieOrder) || errors.Is(err, service.ErrEventFormatMismatch) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			abortWithBracketError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+func (a *Api) GetTie(ctx *gin.Context) {
+	tieId, err := strconv.Atoi(ctx.Params.ByName("tie_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	tie, err := a.svc.GetTie(tieId)
+	if err != nil {
+		if errors.Is(err, service.ErrTieNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	rubbers := make([]dto.MatchInfoResponse, 0, len(tie.Rubbers))
+	for _, mi := range tie.Rubbers {
+		opponents := make([]dto.OpponentResponse, 2)
+		for i, opp := range mi.Opponents {
+			opponents[i] = dto.OpponentResponse{
+				Id:       opp.Id,
+				Name:     opp.Name,
+				IsWinner: opp.IsWinner,
+				Players:  newPlayerResponses(opp.Players),
+			}
+		}
+		rubbers = append(rubbers, dto.MatchInfoResponse{
+			Id:           mi.Id,
+			EventId:      mi.EventId,
+			TieId:        mi.TieId,
+			RubberNumber: mi.RubberNumber,
+			SeasonId:     mi.SeasonId,
+			MatchWeek:    mi.MatchWeek,
+			Deadline:     mi.Deadline,
+			Format:       string(mi.Format),
+			Status:       string(mi.Status),
+			Stage:        string(mi.Stage),
+			Result:       string(mi.Result),
+			Opponents:    opponents,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, dto.TieDetailResponse{
+		TieResponse: dto.TieResponse{
+			Id:           tie.Id,
+			EventId:      tie.EventId,
+			SideA:        tie.SideA,
+			SideB:        tie.SideB,
+			RubbersToWin: tie.RubbersToWin,
+			ScoreA:       tie.ScoreA,
+			ScoreB:       tie.ScoreB,
+			Status:       string(tie.Status),
+			WinnerIsA:    tie.WinnerIsA,
+		},
+		Rubbers: rubbers,
+	})
+}
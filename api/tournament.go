@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/adarsh-a-tw/tt-backend/api/dto"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+	"github.com/adarsh-a-tw/tt-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (a *Api) GetTournaments(ctx *gin.Context) {
+	tournaments, err := a.svc.GetTournaments()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.TournamentResponse, 0, len(tournaments))
+	for _, t := range tournaments {
+		response = append(response, dto.TournamentResponse{Id: t.Id, Name: t.Name})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tournaments": response})
+}
+
+func (a *Api) CreateTournament(ctx *gin.Context) {
+	var requestBody dto.CreateTournamentRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	id, err := a.svc.CreateTournament(requestBody.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrTournamentExists) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.TournamentResponse{Id: id, Name: requestBody.Name})
+}
+
+func (a *Api) GetEvents(ctx *gin.Context) {
+	tournamentId, err := strconv.Atoi(ctx.Params.ByName("tournament_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	events, err := a.svc.GetEvents(tournamentId)
+	if err != nil {
+		abortWithTournamentError(ctx, err)
+		return
+	}
+
+	response := make([]dto.EventResponse, 0, len(events))
+	for _, e := range events {
+		response = append(response, dto.EventResponse{
+			Id:           e.Id,
+			TournamentId: e.TournamentId,
+			Name:         e.Name,
+			Format:       string(e.Format),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"events": response})
+}
+
+func (a *Api) CreateEvent(ctx *gin.Context) {
+	tournamentId, err := strconv.Atoi(ctx.Params.ByName("tournament_id"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
+		return
+	}
+
+	var requestBody dto.CreateEventRequest
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	id, err := a.svc.CreateEvent(tournamentId, requestBody.Name, enums.MatchFormat(requestBody.Format))
+	if err != nil {
+		abortWithTournamentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.EventResponse{
+		Id:           id,
+		TournamentId: tournamentId,
+		Name:         requestBody.Name,
+		Format:       requestBody.Format,
+	})
+}
+
+func abortWithTournamentError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrTournamentNotFound) || errors.Is(err, service.ErrEventNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
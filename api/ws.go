@@ -10,7 +10,7 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-var clients = make(map[*websocket.Conn]*int)
+var clients = make(map[*websocket.Conn]*dto.MatchSubscribeRequest)
 
 func handleClient(c *websocket.Conn, svc service.Service) {
 	defer func() {
@@ -29,16 +29,18 @@ func handleClient(c *websocket.Conn, svc service.Service) {
 		return
 	}
 
-	clients[c] = &m.MatchId
+	clients[c] = &m
 
-	var resp dto.MatchDetailResponse
-	md, err := svc.GetMatchDetails(m.MatchId)
-	if err != nil {
-		resp.Error = err.Error()
-	} else {
-		resp = NewMatchDetailsResponse(md)
+	if m.MatchId != 0 {
+		var resp dto.MatchDetailResponse
+		md, err := svc.GetMatchDetails(m.MatchId)
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp = NewMatchDetailsResponse(md)
+		}
+		notifyMatch(c, svc, resp)
 	}
-	notifyMatch(c, svc, resp)
 
 	for {
 		_, _, err := c.ReadMessage()
@@ -59,6 +61,7 @@ func NewMatchDetailsResponse(md *service.MatchDetail) dto.MatchDetailResponse {
 			Id:       opp.Id,
 			Name:     opp.Name,
 			IsWinner: opp.IsWinner,
+			Players:  newPlayerResponses(opp.Players),
 		})
 	}
 
@@ -71,6 +74,8 @@ func NewMatchDetailsResponse(md *service.MatchDetail) dto.MatchDetailResponse {
 				OppAScore: sl.OppAScore,
 				OppBScore: sl.OppBScore,
 				ScoredByA: sl.ScoredByA,
+				ServedByA: sl.ServedByA,
+				CreatedAt: sl.CreatedAt,
 			})
 		}
 
@@ -80,17 +85,96 @@ func NewMatchDetailsResponse(md *service.MatchDetail) dto.MatchDetailResponse {
 			OpponentAScore: s.OpponentAScore,
 			OpponentBScore: s.OpponentBScore,
 			IsCompleted:    s.IsCompleted,
+			Version:        s.Version,
+			FirstServerIsA: s.FirstServerIsA,
+			IsExpedited:    s.IsExpedited,
+			StartedAt:      s.StartedAt,
 			Logs:           setLogs,
 		})
 	}
 
+	events := make([]dto.MatchEventResponse, 0)
+	for _, e := range md.Events {
+		events = append(events, dto.MatchEventResponse{
+			Id:        e.Id,
+			SetId:     e.SetId,
+			Type:      string(e.Type),
+			IsOppA:    e.IsOppA,
+			Detail:    e.Detail,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	timeline := make([]dto.TimelineEntryResponse, 0)
+	for i, entry := range md.Timeline {
+		timeline = append(timeline, dto.TimelineEntryResponse{
+			SetNumber: entry.SetNumber,
+			Time:      entry.Time,
+		})
+		if entry.Point != nil {
+			timeline[i].Type = "POINT"
+			timeline[i].Point = &dto.SetLogResponse{
+				Id:        entry.Point.Id,
+				OppAScore: entry.Point.OppAScore,
+				OppBScore: entry.Point.OppBScore,
+				ScoredByA: entry.Point.ScoredByA,
+				ServedByA: entry.Point.ServedByA,
+				CreatedAt: entry.Point.CreatedAt,
+			}
+		} else {
+			timeline[i].Type = string(entry.Event.Type)
+			timeline[i].Event = &dto.MatchEventResponse{
+				Id:        entry.Event.Id,
+				SetId:     entry.Event.SetId,
+				Type:      string(entry.Event.Type),
+				IsOppA:    entry.Event.IsOppA,
+				Detail:    entry.Event.Detail,
+				CreatedAt: entry.Event.CreatedAt,
+			}
+		}
+	}
+
+	var tie *dto.TieResponse
+	if md.Tie != nil {
+		tie = &dto.TieResponse{
+			Id:           md.Tie.Id,
+			EventId:      md.Tie.EventId,
+			SideA:        md.Tie.SideA,
+			SideB:        md.Tie.SideB,
+			RubbersToWin: md.Tie.RubbersToWin,
+			ScoreA:       md.Tie.ScoreA,
+			ScoreB:       md.Tie.ScoreB,
+			Status:       string(md.Tie.Status),
+			WinnerIsA:    md.Tie.WinnerIsA,
+		}
+	}
+
 	resp.Data = dto.MatchDetail{
-		Id:        md.Id,
-		Format:    md.Format,
-		Stage:     md.Stage,
-		Status:    md.Status,
-		Opponents: opponents,
-		Sets:      sets,
+		Id:             md.Id,
+		TournamentId:   md.TournamentId,
+		EventId:        md.EventId,
+		GroupId:        md.GroupId,
+		Tie:            tie,
+		RubberNumber:   md.RubberNumber,
+		Bracket:        md.Bracket,
+		Format:         md.Format,
+		Stage:          md.Stage,
+		Status:         md.Status,
+		Result:         md.Result,
+		FormatTemplate: md.FormatTemplate,
+		SetCount:       md.SetCount,
+		GamePoint:      md.GamePoint,
+		ScoringRules:   md.ScoringRules,
+		HandicapA:      md.HandicapA,
+		HandicapB:      md.HandicapB,
+		Opponents:      opponents,
+		Sets:           sets,
+		ServerIsA:      md.ServerIsA,
+		ServerName:     md.ServerName,
+		ReceiverName:   md.ReceiverName,
+		IsExpedited:    md.IsExpedited,
+		Events:         events,
+		Timeline:       timeline,
 	}
 	return resp
 }
@@ -128,13 +212,34 @@ func NotifySubscribers(matchId int, svc service.Service) {
 		resp = NewMatchDetailsResponse(md)
 	}
 
-	for c, mId := range clients {
-		if mId != nil && *mId == matchId {
+	for c, sub := range clients {
+		if sub != nil && isSubscribed(*sub, matchId, md) {
 			go notifyMatch(c, svc, resp)
 		}
 	}
 }
 
+// isSubscribed reports whether a change to the match should be sent to the
+// subscription. md is nil when the match details could not be loaded.
+func isSubscribed(sub dto.MatchSubscribeRequest, matchId int, md *service.MatchDetail) bool {
+	if sub.MatchId != 0 {
+		return sub.MatchId == matchId
+	}
+	if md == nil || (sub.TournamentId == nil && sub.EventId == nil && sub.TieId == nil) {
+		return false
+	}
+	if sub.TournamentId != nil && (md.TournamentId == nil || *md.TournamentId != *sub.TournamentId) {
+		return false
+	}
+	if sub.EventId != nil && (md.EventId == nil || *md.EventId != *sub.EventId) {
+		return false
+	}
+	if sub.TieId != nil && (md.Tie == nil || md.Tie.Id != *sub.TieId) {
+		return false
+	}
+	return true
+}
+
 func serveWs(w http.ResponseWriter, r *http.Request, svc service.Service) {
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool { return true },
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/adarsh-a-tw/tt-backend/api"
 	database "github.com/adarsh-a-tw/tt-backend/db"
@@ -17,7 +18,7 @@ import (
 	"github.com/urfave/cli"
 )
 
-var importChoices = []string{"player", "team", "match"}
+var importChoices = []string{"player", "team", "match", "entry"}
 
 func New(db *sqlx.DB, rdb *redis.Client) *cli.App {
 	app := cli.NewApp()
@@ -63,14 +64,18 @@ func registerCommands(app *cli.App, db *sqlx.DB, rdb *redis.Client) {
 
 				reader := csv.NewReader(csvFile)
 				svc := service.NewService(database.NewRepository(db))
+				tournamentId := optionalIntFlag(c, "tournament-id")
+				eventId := optionalIntFlag(c, "event-id")
 
 				switch importType {
 				case "player":
-					return createPlayers(reader, svc)
+					return createPlayers(reader, svc, tournamentId)
 				case "team":
-					return createTeams(reader, svc)
+					return createTeams(reader, svc, tournamentId)
 				case "match":
-					return createMatches(reader, svc)
+					return createMatches(reader, svc, eventId)
+				case "entry":
+					return createEntries(reader, svc, eventId)
 				default:
 					log.Println("Unknown resource type")
 				}
@@ -87,6 +92,107 @@ func registerCommands(app *cli.App, db *sqlx.DB, rdb *redis.Client) {
 					Name:  "csv",
 					Usage: "CSV file containing import data",
 				},
+				cli.IntFlag{
+					Name:  "tournament-id",
+					Usage: "Tournament the imported players or teams are registered for",
+				},
+				cli.IntFlag{
+					Name:  "event-id",
+					Usage: "Event the imported matches or entries belong to, unless set by an event_id column",
+				},
+			},
+		},
+		{
+			Name:        "swiss-round",
+			Description: "Pair the entries of a swiss event for its next round",
+			Action: func(c *cli.Context) error {
+				eventId := optionalIntFlag(c, "event-id")
+				if eventId == nil {
+					return fmt.Errorf("event id not specified")
+				}
+
+				svc := service.NewService(database.NewRepository(db))
+				settings := service.MatchSettings{
+					FormatTemplate: c.String("format-template"),
+					MaxSets:        c.Int("max-sets"),
+					GamePoint:      c.Int("game-point"),
+					ScoringRules:   enums.ScoringRuleSet(c.String("scoring-rules")),
+				}
+
+				round, err := svc.GenerateSwissRound(*eventId, settings)
+				if err != nil {
+					return err
+				}
+				log.Printf("Round %d paired successfully.\n", round)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "event-id",
+					Usage: "Swiss event to pair",
+				},
+				cli.StringFlag{
+					Name:  "format-template",
+					Usage: "Match format template of the new matches",
+				},
+				cli.IntFlag{
+					Name:  "max-sets",
+					Usage: "Maximum number of sets of the new matches",
+				},
+				cli.IntFlag{
+					Name:  "game-point",
+					Usage: "Points needed to win a set of the new matches",
+				},
+				cli.StringFlag{
+					Name:  "scoring-rules",
+					Usage: "Scoring rules of the new matches",
+				},
+			},
+		},
+		{
+			Name:        "verify-draw",
+			Description: "Repeat the stored draw of an event from its random seed and check the bracket against it",
+			Action: func(c *cli.Context) error {
+				eventId := optionalIntFlag(c, "event-id")
+				if eventId == nil {
+					return fmt.Errorf("event id not specified")
+				}
+
+				svc := service.NewService(database.NewRepository(db))
+				verification, err := svc.VerifyDraw(*eventId)
+				if err != nil {
+					return err
+				}
+				for _, mismatch := range verification.Mismatches {
+					log.Println(mismatch)
+				}
+				if len(verification.Mismatches) > 0 {
+					return fmt.Errorf("draw of event %d does not match random seed %d", *eventId, verification.RandomSeed)
+				}
+				log.Printf("Draw of event %d verified with random seed %d.\n", *eventId, verification.RandomSeed)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "event-id",
+					Usage: "Event whose draw is verified",
+				},
+			},
+		},
+		{
+			Name:        "season-walkovers",
+			Description: "Walk over the season fixtures not started by their deadline",
+			Action: func(c *cli.Context) error {
+				svc := service.NewService(database.NewRepository(db))
+				matchIds, err := svc.ApplySeasonWalkovers(time.Now())
+				if err != nil {
+					return err
+				}
+				for _, matchId := range matchIds {
+					api.PublishMatchChange(matchId, rdb)
+				}
+				log.Printf("%d fixtures walked over.\n", len(matchIds))
+				return nil
 			},
 		},
 	}
@@ -101,7 +207,7 @@ func runServer(db *sqlx.DB, rdb *redis.Client) error {
 	return api.Serve(addr)
 }
 
-func createPlayers(reader *csv.Reader, svc service.Service) error {
+func createPlayers(reader *csv.Reader, svc service.Service, tournamentId *int) error {
 	records, err := reader.ReadAll()
 	if err != nil {
 		return err
@@ -120,7 +226,24 @@ func createPlayers(reader *csv.Reader, svc service.Service) error {
 			return errors.New("field not found in csv: name")
 		}
 
-		err = svc.CreatePlayer(record[nameIndex])
+		profile := service.PlayerProfile{
+			Name:       record[nameIndex],
+			Club:       optionalString(record, keys, "club"),
+			Country:    optionalString(record, keys, "country"),
+			Handedness: enums.Handedness(optionalString(record, keys, "handedness")),
+			GripStyle:  enums.GripStyle(optionalString(record, keys, "grip_style")),
+			Gender:     enums.Gender(optionalString(record, keys, "gender")),
+			PhotoUrl:   optionalString(record, keys, "photo_url"),
+		}
+		if birth_year := optionalString(record, keys, "birth_year"); birth_year != "" {
+			year, err := strconv.Atoi(birth_year)
+			if err != nil {
+				return err
+			}
+			profile.BirthYear = &year
+		}
+
+		_, err = svc.CreatePlayer(profile, tournamentId)
 		if err != nil {
 			return err
 		}
@@ -129,7 +252,10 @@ func createPlayers(reader *csv.Reader, svc service.Service) error {
 	return nil
 }
 
-func createTeams(reader *csv.Reader, svc service.Service) error {
+// createTeams links each team to its players by the player_a_id and
+// player_b_id columns, or by looking up the names in the player_a and
+// player_b columns.
+func createTeams(reader *csv.Reader, svc service.Service, tournamentId *int) error {
 	records, err := reader.ReadAll()
 	if err != nil {
 		return err
@@ -143,16 +269,16 @@ func createTeams(reader *csv.Reader, svc service.Service) error {
 			}
 			continue
 		}
-		playerAIndex, ok := keys["player_a"]
-		if !ok {
-			return errors.New("field not found in csv: player_a")
+		player_a_id, err := teamPlayerId(record, keys, "player_a", svc, tournamentId)
+		if err != nil {
+			return err
 		}
-		playerBIndex, ok := keys["player_b"]
-		if !ok {
-			return errors.New("field not found in csv: player_b")
+		player_b_id, err := teamPlayerId(record, keys, "player_b", svc, tournamentId)
+		if err != nil {
+			return err
 		}
 
-		err = svc.CreateTeam(record[playerAIndex], record[playerBIndex])
+		err = svc.CreateTeam(player_a_id, player_b_id, tournamentId)
 		if err != nil {
 			return err
 		}
@@ -161,7 +287,22 @@ func createTeams(reader *csv.Reader, svc service.Service) error {
 	return nil
 }
 
-func createMatches(reader *csv.Reader, svc service.Service) error {
+func teamPlayerId(record []string, keys map[string]int, key string, svc service.Service, tournamentId *int) (int, error) {
+	if index, ok := keys[key+"_id"]; ok {
+		return strconv.Atoi(record[index])
+	}
+	index, ok := keys[key]
+	if !ok {
+		return 0, fmt.Errorf("field not found in csv: %s_id", key)
+	}
+	id, err := svc.FindPlayerId(record[index], tournamentId)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s", record[index], err)
+	}
+	return id, nil
+}
+
+func createMatches(reader *csv.Reader, svc service.Service, eventId *int) error {
 	records, err := reader.ReadAll()
 	if err != nil {
 		return err
@@ -174,7 +315,10 @@ func createMatches(reader *csv.Reader, svc service.Service) error {
 				keys[value] = j
 			}
 
-			keyNames := []string{"format", "stage", "opp_a_id", "opp_b_id", "max_sets", "game_point"}
+			keyNames := []string{"format", "stage", "opp_a_id", "opp_b_id"}
+			if _, ok := keys["format_template"]; !ok {
+				keyNames = append(keyNames, "max_sets", "game_point")
+			}
 
 			for _, key := range keyNames {
 				_, ok := keys[key]
@@ -195,23 +339,56 @@ func createMatches(reader *csv.Reader, svc service.Service) error {
 		if err != nil {
 			return err
 		}
-		max_sets, err := strconv.Atoi(record[keys["max_sets"]])
+		format_template := ""
+		if index, ok := keys["format_template"]; ok {
+			format_template = record[index]
+		}
+		max_sets, err := optionalInt(record, keys, "max_sets")
+		if err != nil {
+			return err
+		}
+		game_point, err := optionalInt(record, keys, "game_point")
+		if err != nil {
+			return err
+		}
+		var scoring_rules enums.ScoringRuleSet
+		if index, ok := keys["scoring_rules"]; ok && record[index] != "" {
+			scoring_rules = enums.ScoringRuleSet(record[index])
+		}
+		handicap_a, err := optionalInt(record, keys, "handicap_a")
 		if err != nil {
 			return err
 		}
-		game_point, err := strconv.Atoi(record[keys["game_point"]])
+		handicap_b, err := optionalInt(record, keys, "handicap_b")
 		if err != nil {
 			return err
 		}
+		event_id := eventId
+		if index, ok := keys["event_id"]; ok && record[index] != "" {
+			id, err := strconv.Atoi(record[index])
+			if err != nil {
+				return err
+			}
+			event_id = &id
+		}
+		settings := service.MatchSettings{
+			EventId:        event_id,
+			FormatTemplate: format_template,
+			MaxSets:        max_sets,
+			GamePoint:      game_point,
+			ScoringRules:   scoring_rules,
+			HandicapA:      handicap_a,
+			HandicapB:      handicap_b,
+		}
 
 		switch format {
 		case enums.Singles:
-			err = svc.CreateSinglesMatch(stage, opp_a_id, opp_b_id, max_sets, game_point)
+			err = svc.CreateSinglesMatch(stage, opp_a_id, opp_b_id, settings)
 			if err != nil {
 				return err
 			}
 		case enums.Doubles:
-			err = svc.CreateDoublesMatch(stage, opp_a_id, opp_b_id, max_sets, game_point)
+			err = svc.CreateDoublesMatch(stage, opp_a_id, opp_b_id, settings)
 			if err != nil {
 				return err
 			}
@@ -222,3 +399,86 @@ func createMatches(reader *csv.Reader, svc service.Service) error {
 	log.Println("Data imported successfully.")
 	return nil
 }
+
+func createEntries(reader *csv.Reader, svc service.Service, eventId *int) error {
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]int{}
+	entries := map[int][]service.BracketEntry{}
+	eventIds := []int{}
+	for i, record := range records {
+		if i == 0 {
+			for j, value := range record {
+				keys[value] = j
+			}
+			continue
+		}
+		opponentIndex, ok := keys["opponent_id"]
+		if !ok {
+			return errors.New("field not found in csv: opponent_id")
+		}
+		opponent_id, err := strconv.Atoi(record[opponentIndex])
+		if err != nil {
+			return err
+		}
+		seed, err := optionalInt(record, keys, "seed")
+		if err != nil {
+			return err
+		}
+		event_id := eventId
+		if index, ok := keys["event_id"]; ok && record[index] != "" {
+			id, err := strconv.Atoi(record[index])
+			if err != nil {
+				return err
+			}
+			event_id = &id
+		}
+		if event_id == nil {
+			return errors.New("event not specified for entry")
+		}
+
+		if _, ok := entries[*event_id]; !ok {
+			eventIds = append(eventIds, *event_id)
+		}
+		entries[*event_id] = append(entries[*event_id], service.BracketEntry{OpponentId: opponent_id, Seed: seed})
+	}
+
+	for _, id := range eventIds {
+		if err := svc.AddEventEntries(id, entries[id]); err != nil {
+			return err
+		}
+	}
+	log.Println("Data imported successfully.")
+	return nil
+}
+
+// optionalInt reads an integer column that may be missing from the csv or left
+// empty, in which case it defaults to 0.
+func optionalInt(record []string, keys map[string]int, key string) (int, error) {
+	index, ok := keys[key]
+	if !ok || record[index] == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(record[index])
+}
+
+// optionalString reads a column that may be missing from the csv.
+func optionalString(record []string, keys map[string]int, key string) string {
+	index, ok := keys[key]
+	if !ok {
+		return ""
+	}
+	return record[index]
+}
+
+// optionalIntFlag returns nil when the flag was not given.
+func optionalIntFlag(c *cli.Context, name string) *int {
+	if !c.IsSet(name) {
+		return nil
+	}
+	value := c.Int(name)
+	return &value
+}
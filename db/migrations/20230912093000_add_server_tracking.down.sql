@@ -0,0 +1,2 @@
+ALTER TABLE set_log DROP COLUMN IF EXISTS served_by_a;
+ALTER TABLE match DROP COLUMN IF EXISTS first_server_is_a;
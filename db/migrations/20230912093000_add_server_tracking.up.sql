@@ -0,0 +1,3 @@
+-- Server tracking
+ALTER TABLE match ADD COLUMN IF NOT EXISTS first_server_is_a BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE set_log ADD COLUMN IF NOT EXISTS served_by_a BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,2 @@
+-- Scoring rules
+ALTER TABLE match ADD COLUMN IF NOT EXISTS scoring_rules TEXT NOT NULL DEFAULT 'STANDARD';
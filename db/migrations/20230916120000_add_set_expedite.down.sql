@@ -0,0 +1,2 @@
+ALTER TABLE set DROP COLUMN IF EXISTS expedite_from_point;
+ALTER TABLE set DROP COLUMN IF EXISTS started_at;
@@ -0,0 +1,3 @@
+-- Expedite system
+ALTER TABLE set ADD COLUMN IF NOT EXISTS started_at TIMESTAMP NOT NULL DEFAULT now();
+ALTER TABLE set ADD COLUMN IF NOT EXISTS expedite_from_point INT;
@@ -0,0 +1,3 @@
+-- Match result
+ALTER TABLE match ADD COLUMN IF NOT EXISTS result TEXT NOT NULL DEFAULT '';
+UPDATE match SET result = 'COMPLETED' WHERE status = 'PAST';
@@ -0,0 +1,2 @@
+ALTER TABLE set_log DROP COLUMN IF EXISTS created_at;
+DROP TABLE IF EXISTS match_event;
@@ -0,0 +1,14 @@
+-- Match Event table
+CREATE TABLE IF NOT EXISTS match_event (
+    id SERIAL PRIMARY KEY NOT NULL,
+    match_id INT NOT NULL,
+    set_id INT,
+    type TEXT NOT NULL,
+    is_opp_a BOOLEAN,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (match_id) REFERENCES match(id),
+    FOREIGN KEY (set_id) REFERENCES set(id)
+);
+
+ALTER TABLE set_log ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT now();
@@ -0,0 +1,2 @@
+ALTER TABLE set DROP COLUMN IF EXISTS first_receiver_is_player_a;
+ALTER TABLE set DROP COLUMN IF EXISTS first_server_is_player_a;
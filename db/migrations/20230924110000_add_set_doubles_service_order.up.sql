@@ -0,0 +1,3 @@
+-- Doubles service order
+ALTER TABLE set ADD COLUMN IF NOT EXISTS first_server_is_player_a BOOLEAN;
+ALTER TABLE set ADD COLUMN IF NOT EXISTS first_receiver_is_player_a BOOLEAN;
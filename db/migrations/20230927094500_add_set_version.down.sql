@@ -0,0 +1 @@
+ALTER TABLE set DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+-- Set version for optimistic locking
+ALTER TABLE set ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Score Redo table
+CREATE TABLE IF NOT EXISTS score_redo (
+    id SERIAL PRIMARY KEY NOT NULL,
+    match_id INT NOT NULL,
+    set_number INT NOT NULL,
+    scored_by_a BOOLEAN NOT NULL,
+    FOREIGN KEY (match_id) REFERENCES match(id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE match DROP COLUMN IF EXISTS handicap_b;
+ALTER TABLE match DROP COLUMN IF EXISTS handicap_a;
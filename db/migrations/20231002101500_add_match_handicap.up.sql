@@ -0,0 +1,3 @@
+-- Handicap starting scores
+ALTER TABLE match ADD COLUMN IF NOT EXISTS handicap_a INT NOT NULL DEFAULT 0;
+ALTER TABLE match ADD COLUMN IF NOT EXISTS handicap_b INT NOT NULL DEFAULT 0;
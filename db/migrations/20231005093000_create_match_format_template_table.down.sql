@@ -0,0 +1,2 @@
+ALTER TABLE match DROP COLUMN IF EXISTS format_template;
+DROP TABLE IF EXISTS match_format_template;
@@ -0,0 +1,17 @@
+-- Match Format Template table
+CREATE TABLE IF NOT EXISTS match_format_template (
+    id SERIAL PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    set_count INT NOT NULL,
+    game_point INT NOT NULL,
+    scoring_rules TEXT NOT NULL DEFAULT 'STANDARD'
+);
+
+INSERT INTO match_format_template (name, set_count, game_point, scoring_rules) VALUES
+    ('best of 3 to 11', 3, 11, 'STANDARD'),
+    ('best of 5 to 11', 5, 11, 'STANDARD'),
+    ('best of 7 to 11', 7, 11, 'STANDARD'),
+    ('best of 3 to 21', 3, 21, 'STANDARD')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS format_template TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE team DROP COLUMN IF EXISTS tournament_id;
+ALTER TABLE player DROP COLUMN IF EXISTS tournament_id;
+ALTER TABLE match DROP COLUMN IF EXISTS event_id;
+DROP TABLE IF EXISTS event;
+DROP TABLE IF EXISTS tournament;
@@ -0,0 +1,19 @@
+-- Tournament table
+CREATE TABLE IF NOT EXISTS tournament (
+    id SERIAL PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE
+);
+
+-- Event table
+CREATE TABLE IF NOT EXISTS event (
+    id SERIAL PRIMARY KEY NOT NULL,
+    tournament_id INT NOT NULL,
+    name TEXT NOT NULL,
+    format TEXT NOT NULL,
+    FOREIGN KEY (tournament_id) REFERENCES tournament(id),
+    UNIQUE (tournament_id, name)
+);
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS event_id INT REFERENCES event(id);
+ALTER TABLE player ADD COLUMN IF NOT EXISTS tournament_id INT REFERENCES tournament(id);
+ALTER TABLE team ADD COLUMN IF NOT EXISTS tournament_id INT REFERENCES tournament(id);
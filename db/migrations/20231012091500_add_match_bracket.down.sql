@@ -0,0 +1,4 @@
+ALTER TABLE match DROP COLUMN IF EXISTS next_match_slot_is_a;
+ALTER TABLE match DROP COLUMN IF EXISTS next_match_id;
+ALTER TABLE match DROP COLUMN IF EXISTS bracket_position;
+ALTER TABLE match DROP COLUMN IF EXISTS bracket_round;
@@ -0,0 +1,4 @@
+ALTER TABLE match ADD COLUMN IF NOT EXISTS bracket_round INT;
+ALTER TABLE match ADD COLUMN IF NOT EXISTS bracket_position INT;
+ALTER TABLE match ADD COLUMN IF NOT EXISTS next_match_id INT REFERENCES match(id);
+ALTER TABLE match ADD COLUMN IF NOT EXISTS next_match_slot_is_a BOOLEAN;
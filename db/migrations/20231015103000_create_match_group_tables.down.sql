@@ -0,0 +1,4 @@
+ALTER TABLE match DROP COLUMN IF EXISTS group_round;
+ALTER TABLE match DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS match_group_entry;
+DROP TABLE IF EXISTS match_group;
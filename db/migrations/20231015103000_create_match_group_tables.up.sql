@@ -0,0 +1,20 @@
+-- Match Group table
+CREATE TABLE IF NOT EXISTS match_group (
+    id SERIAL PRIMARY KEY NOT NULL,
+    event_id INT NOT NULL,
+    name TEXT NOT NULL,
+    FOREIGN KEY (event_id) REFERENCES event(id),
+    UNIQUE (event_id, name)
+);
+
+-- Match Group Entry table
+CREATE TABLE IF NOT EXISTS match_group_entry (
+    group_id INT NOT NULL,
+    opponent_id INT NOT NULL,
+    position INT NOT NULL,
+    FOREIGN KEY (group_id) REFERENCES match_group(id),
+    PRIMARY KEY (group_id, opponent_id)
+);
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS group_id INT REFERENCES match_group(id);
+ALTER TABLE match ADD COLUMN IF NOT EXISTS group_round INT;
@@ -0,0 +1,3 @@
+ALTER TABLE match DROP COLUMN IF EXISTS rubber_number;
+ALTER TABLE match DROP COLUMN IF EXISTS tie_id;
+DROP TABLE IF EXISTS tie;
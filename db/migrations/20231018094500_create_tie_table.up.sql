@@ -0,0 +1,15 @@
+-- Tie table
+CREATE TABLE IF NOT EXISTS tie (
+    id SERIAL PRIMARY KEY NOT NULL,
+    event_id INT REFERENCES event(id),
+    side_a TEXT NOT NULL,
+    side_b TEXT NOT NULL,
+    rubbers_to_win INT NOT NULL,
+    score_a INT NOT NULL DEFAULT 0,
+    score_b INT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    winner_is_a BOOLEAN
+);
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS tie_id INT REFERENCES tie(id);
+ALTER TABLE match ADD COLUMN IF NOT EXISTS rubber_number INT;
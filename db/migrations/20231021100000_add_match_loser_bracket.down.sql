@@ -0,0 +1,3 @@
+ALTER TABLE match DROP COLUMN IF EXISTS loser_next_match_slot_is_a;
+ALTER TABLE match DROP COLUMN IF EXISTS loser_next_match_id;
+ALTER TABLE match DROP COLUMN IF EXISTS bracket;
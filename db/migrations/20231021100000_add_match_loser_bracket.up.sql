@@ -0,0 +1,5 @@
+ALTER TABLE match ADD COLUMN IF NOT EXISTS bracket TEXT NOT NULL DEFAULT '';
+ALTER TABLE match ADD COLUMN IF NOT EXISTS loser_next_match_id INT REFERENCES match(id);
+ALTER TABLE match ADD COLUMN IF NOT EXISTS loser_next_match_slot_is_a BOOLEAN;
+
+UPDATE match SET bracket = 'MAIN' WHERE bracket_round IS NOT NULL;
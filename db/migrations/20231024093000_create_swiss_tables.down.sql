@@ -0,0 +1,3 @@
+ALTER TABLE match DROP COLUMN IF EXISTS swiss_round;
+DROP TABLE IF EXISTS swiss_bye;
+DROP TABLE IF EXISTS event_entry;
@@ -0,0 +1,19 @@
+-- Event Entry table
+CREATE TABLE IF NOT EXISTS event_entry (
+    event_id INT NOT NULL,
+    opponent_id INT NOT NULL,
+    seed INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (event_id) REFERENCES event(id),
+    PRIMARY KEY (event_id, opponent_id)
+);
+
+-- Swiss Bye table
+CREATE TABLE IF NOT EXISTS swiss_bye (
+    event_id INT NOT NULL,
+    round INT NOT NULL,
+    opponent_id INT NOT NULL,
+    FOREIGN KEY (event_id) REFERENCES event(id),
+    PRIMARY KEY (event_id, round)
+);
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS swiss_round INT;
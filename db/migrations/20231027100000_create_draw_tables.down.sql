@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS draw_entry;
+DROP TABLE IF EXISTS draw;
@@ -0,0 +1,20 @@
+-- Draw table
+CREATE TABLE IF NOT EXISTS draw (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL UNIQUE,
+    random_seed BIGINT NOT NULL,
+    draw_type TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (event_id) REFERENCES event(id)
+);
+
+-- Draw Entry table
+CREATE TABLE IF NOT EXISTS draw_entry (
+    draw_id INT NOT NULL,
+    opponent_id INT NOT NULL,
+    seed INT NOT NULL DEFAULT 0,
+    entry_order INT NOT NULL,
+    slot INT NOT NULL,
+    FOREIGN KEY (draw_id) REFERENCES draw(id),
+    PRIMARY KEY (draw_id, opponent_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE match DROP COLUMN IF EXISTS deadline;
+ALTER TABLE match DROP COLUMN IF EXISTS match_week;
+ALTER TABLE match DROP COLUMN IF EXISTS season_id;
+DROP TABLE IF EXISTS season_entry;
+DROP TABLE IF EXISTS season;
@@ -0,0 +1,21 @@
+-- Season table
+CREATE TABLE IF NOT EXISTS season (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    format TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    week_days INT NOT NULL DEFAULT 7
+);
+
+-- Season Entry table
+CREATE TABLE IF NOT EXISTS season_entry (
+    season_id INT NOT NULL,
+    opponent_id INT NOT NULL,
+    position INT NOT NULL,
+    FOREIGN KEY (season_id) REFERENCES season(id),
+    PRIMARY KEY (season_id, opponent_id)
+);
+
+ALTER TABLE match ADD COLUMN IF NOT EXISTS season_id INT REFERENCES season(id);
+ALTER TABLE match ADD COLUMN IF NOT EXISTS match_week INT;
+ALTER TABLE match ADD COLUMN IF NOT EXISTS deadline TIMESTAMP;
@@ -0,0 +1,11 @@
+ALTER TABLE team ADD COLUMN IF NOT EXISTS player_a TEXT;
+ALTER TABLE team ADD COLUMN IF NOT EXISTS player_b TEXT;
+
+UPDATE team SET
+    player_a = (SELECT name FROM player WHERE player.id = team.player_a_id),
+    player_b = (SELECT name FROM player WHERE player.id = team.player_b_id);
+
+ALTER TABLE team ALTER COLUMN player_a SET NOT NULL;
+ALTER TABLE team ALTER COLUMN player_b SET NOT NULL;
+ALTER TABLE team DROP COLUMN IF EXISTS player_b_id;
+ALTER TABLE team DROP COLUMN IF EXISTS player_a_id;
@@ -0,0 +1,39 @@
+ALTER TABLE team ADD COLUMN IF NOT EXISTS player_a_id INT REFERENCES player(id);
+ALTER TABLE team ADD COLUMN IF NOT EXISTS player_b_id INT REFERENCES player(id);
+
+-- Players named in a team but never registered get a player row, scoped to
+-- the tournament of the team.
+INSERT INTO player (name, tournament_id)
+SELECT DISTINCT names.name, names.tournament_id
+FROM (
+    SELECT player_a AS name, tournament_id FROM team
+    UNION
+    SELECT player_b AS name, tournament_id FROM team
+) AS names
+WHERE NOT EXISTS (
+    SELECT 1 FROM player
+    WHERE player.name = names.name
+        AND (player.tournament_id IS NULL OR player.tournament_id IS NOT DISTINCT FROM names.tournament_id)
+);
+
+-- A name is matched to the player of the same tournament first, then to a
+-- player that is not scoped to any tournament.
+UPDATE team SET player_a_id = (
+    SELECT player.id FROM player
+    WHERE player.name = team.player_a
+        AND (player.tournament_id IS NULL OR player.tournament_id IS NOT DISTINCT FROM team.tournament_id)
+    ORDER BY player.tournament_id IS NULL, player.id
+    LIMIT 1
+);
+UPDATE team SET player_b_id = (
+    SELECT player.id FROM player
+    WHERE player.name = team.player_b
+        AND (player.tournament_id IS NULL OR player.tournament_id IS NOT DISTINCT FROM team.tournament_id)
+    ORDER BY player.tournament_id IS NULL, player.id
+    LIMIT 1
+);
+
+ALTER TABLE team ALTER COLUMN player_a_id SET NOT NULL;
+ALTER TABLE team ALTER COLUMN player_b_id SET NOT NULL;
+ALTER TABLE team DROP COLUMN IF EXISTS player_a;
+ALTER TABLE team DROP COLUMN IF EXISTS player_b;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS player_lower_name_idx;
+
+ALTER TABLE player DROP COLUMN IF EXISTS photo_url;
+ALTER TABLE player DROP COLUMN IF EXISTS gender;
+ALTER TABLE player DROP COLUMN IF EXISTS birth_year;
+ALTER TABLE player DROP COLUMN IF EXISTS grip_style;
+ALTER TABLE player DROP COLUMN IF EXISTS handedness;
+ALTER TABLE player DROP COLUMN IF EXISTS country;
+ALTER TABLE player DROP COLUMN IF EXISTS club;
@@ -0,0 +1,9 @@
+ALTER TABLE player ADD COLUMN IF NOT EXISTS club TEXT NOT NULL DEFAULT '';
+ALTER TABLE player ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
+ALTER TABLE player ADD COLUMN IF NOT EXISTS handedness TEXT NOT NULL DEFAULT '';
+ALTER TABLE player ADD COLUMN IF NOT EXISTS grip_style TEXT NOT NULL DEFAULT '';
+ALTER TABLE player ADD COLUMN IF NOT EXISTS birth_year INT;
+ALTER TABLE player ADD COLUMN IF NOT EXISTS gender TEXT NOT NULL DEFAULT '';
+ALTER TABLE player ADD COLUMN IF NOT EXISTS photo_url TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS player_lower_name_idx ON player (lower(name));
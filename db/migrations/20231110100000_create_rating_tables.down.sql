@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS rating_change;
+DROP TABLE IF EXISTS rating;
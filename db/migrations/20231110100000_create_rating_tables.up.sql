@@ -0,0 +1,22 @@
+-- Rating table, of a player for SINGLES or of a team for DOUBLES
+CREATE TABLE IF NOT EXISTS rating (
+    format TEXT NOT NULL,
+    opponent_id INT NOT NULL,
+    rating DOUBLE PRECISION NOT NULL,
+    matches_rated INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (format, opponent_id)
+);
+
+-- Rating Change table
+CREATE TABLE IF NOT EXISTS rating_change (
+    id SERIAL PRIMARY KEY,
+    match_id INT NOT NULL,
+    format TEXT NOT NULL,
+    opponent_id INT NOT NULL,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (match_id) REFERENCES match(id)
+);
+
+CREATE INDEX IF NOT EXISTS rating_change_opponent_idx ON rating_change (format, opponent_id);
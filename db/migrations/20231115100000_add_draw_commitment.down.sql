@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS draw_commitment;
+ALTER TABLE draw DROP COLUMN IF EXISTS shuffle;
@@ -0,0 +1,11 @@
+-- Draws held before the shuffle was specified were shuffled with Go's math/rand
+ALTER TABLE draw ADD COLUMN IF NOT EXISTS shuffle TEXT NOT NULL DEFAULT 'GO_MATH_RAND';
+
+-- Draw Commitment table, the hash of a random seed fixed before the draw
+CREATE TABLE IF NOT EXISTS draw_commitment (
+    event_id INT PRIMARY KEY,
+    seed_hash TEXT NOT NULL,
+    committed_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (event_id) REFERENCES event(id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE match ALTER COLUMN deadline TYPE TIMESTAMP USING deadline AT TIME ZONE 'UTC';
+ALTER TABLE season ALTER COLUMN starts_at TYPE TIMESTAMP USING starts_at AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- Times stored without a time zone were written and compared in UTC
+ALTER TABLE season ALTER COLUMN starts_at TYPE TIMESTAMPTZ USING starts_at AT TIME ZONE 'UTC';
+ALTER TABLE match ALTER COLUMN deadline TYPE TIMESTAMPTZ USING deadline AT TIME ZONE 'UTC';
@@ -0,0 +1,28 @@
+DROP INDEX IF EXISTS rating_change_player_idx;
+CREATE INDEX IF NOT EXISTS rating_change_opponent_idx ON rating_change (format, player_id);
+
+-- Each pair goes back to its first team
+UPDATE rating_change
+SET player_id = pair_team.id
+FROM (
+    SELECT MIN(id) AS id, LEAST(player_a_id, player_b_id) AS player_id, GREATEST(player_a_id, player_b_id) AS partner_id
+    FROM team GROUP BY 2, 3
+) AS pair_team
+WHERE rating_change.format = 'DOUBLES'
+    AND rating_change.player_id = pair_team.player_id AND rating_change.partner_id = pair_team.partner_id;
+
+ALTER TABLE rating DROP CONSTRAINT IF EXISTS rating_pkey;
+UPDATE rating
+SET player_id = pair_team.id
+FROM (
+    SELECT MIN(id) AS id, LEAST(player_a_id, player_b_id) AS player_id, GREATEST(player_a_id, player_b_id) AS partner_id
+    FROM team GROUP BY 2, 3
+) AS pair_team
+WHERE rating.format = 'DOUBLES'
+    AND rating.player_id = pair_team.player_id AND rating.partner_id = pair_team.partner_id;
+ALTER TABLE rating ADD PRIMARY KEY (format, player_id);
+
+ALTER TABLE rating_change DROP COLUMN IF EXISTS partner_id;
+ALTER TABLE rating_change RENAME COLUMN player_id TO opponent_id;
+ALTER TABLE rating DROP COLUMN IF EXISTS partner_id;
+ALTER TABLE rating RENAME COLUMN player_id TO opponent_id;
@@ -0,0 +1,34 @@
+-- Ratings are kept per player for SINGLES and per pair of players for
+-- DOUBLES, with the lower player id first and partner_id 0 for SINGLES
+ALTER TABLE rating RENAME COLUMN opponent_id TO player_id;
+ALTER TABLE rating ADD COLUMN IF NOT EXISTS partner_id INT NOT NULL DEFAULT 0;
+ALTER TABLE rating DROP CONSTRAINT IF EXISTS rating_pkey;
+ALTER TABLE rating_change RENAME COLUMN opponent_id TO player_id;
+ALTER TABLE rating_change ADD COLUMN IF NOT EXISTS partner_id INT NOT NULL DEFAULT 0;
+
+UPDATE rating_change
+SET player_id = LEAST(team.player_a_id, team.player_b_id),
+    partner_id = GREATEST(team.player_a_id, team.player_b_id)
+FROM team
+WHERE rating_change.format = 'DOUBLES' AND team.id = rating_change.player_id;
+
+-- Teams of the same pair in different tournaments share one rating, which
+-- takes the changes of every one of them on top of the initial 1500
+WITH pairs AS (
+    SELECT LEAST(team.player_a_id, team.player_b_id) AS player_id,
+        GREATEST(team.player_a_id, team.player_b_id) AS partner_id,
+        SUM(rating.rating - 1500) AS change,
+        SUM(rating.matches_rated) AS matches_rated
+    FROM rating JOIN team ON team.id = rating.player_id
+    WHERE rating.format = 'DOUBLES'
+    GROUP BY 1, 2
+), removed AS (
+    DELETE FROM rating WHERE format = 'DOUBLES'
+)
+INSERT INTO rating (format, player_id, partner_id, rating, matches_rated)
+SELECT 'DOUBLES', player_id, partner_id, 1500 + change, matches_rated FROM pairs;
+
+ALTER TABLE rating ADD PRIMARY KEY (format, player_id, partner_id);
+
+DROP INDEX IF EXISTS rating_change_opponent_idx;
+CREATE INDEX IF NOT EXISTS rating_change_player_idx ON rating_change (format, player_id, partner_id);
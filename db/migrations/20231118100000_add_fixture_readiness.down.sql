@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fixture_readiness;
+ALTER TABLE season DROP COLUMN IF EXISTS walkover_rule;
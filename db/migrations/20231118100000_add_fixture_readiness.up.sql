@@ -0,0 +1,12 @@
+-- The side that wins a fixture walked over at its deadline when neither or both
+-- opponents reported ready
+ALTER TABLE season ADD COLUMN IF NOT EXISTS walkover_rule TEXT NOT NULL DEFAULT 'HOME';
+
+-- Fixture Readiness table, the opponents that reported ready for a season fixture
+CREATE TABLE IF NOT EXISTS fixture_readiness (
+    match_id INT NOT NULL,
+    opponent_id INT NOT NULL,
+    reported_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (match_id) REFERENCES match(id),
+    PRIMARY KEY (match_id, opponent_id)
+);
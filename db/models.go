@@ -1,32 +1,77 @@
 package db
 
+import "time"
+
 type Match struct {
-	Id        int    `db:"id"`
-	Stage     string `db:"stage"`
-	Format    string `db:"format"`
-	GamePoint int    `db:"game_point"`
-	SetCount  int    `db:"set_count"`
-	Status    string `db:"status"`
+	Id             int    `db:"id"`
+	Stage          string `db:"stage"`
+	Format         string `db:"format"`
+	GamePoint      int    `db:"game_point"`
+	SetCount       int    `db:"set_count"`
+	Status         string `db:"status"`
+	FirstServerIsA bool   `db:"first_server_is_a"`
+	ScoringRules   string `db:"scoring_rules"`
+	Result         string `db:"result"`
+	HandicapA      int    `db:"handicap_a"`
+	HandicapB      int    `db:"handicap_b"`
+	FormatTemplate string `db:"format_template"`
+	EventId        *int   `db:"event_id"`
+
+	BracketRound     *int  `db:"bracket_round"`
+	BracketPosition  *int  `db:"bracket_position"`
+	NextMatchId      *int  `db:"next_match_id"`
+	NextMatchSlotIsA *bool `db:"next_match_slot_is_a"`
+
+	Bracket               string `db:"bracket"`
+	LoserNextMatchId      *int   `db:"loser_next_match_id"`
+	LoserNextMatchSlotIsA *bool  `db:"loser_next_match_slot_is_a"`
+
+	GroupId    *int `db:"group_id"`
+	GroupRound *int `db:"group_round"`
+
+	TieId        *int `db:"tie_id"`
+	RubberNumber *int `db:"rubber_number"`
+
+	SwissRound *int `db:"swiss_round"`
+
+	SeasonId  *int       `db:"season_id"`
+	MatchWeek *int       `db:"match_week"`
+	Deadline  *time.Time `db:"deadline"`
 }
 
 type Set struct {
-	Id             int  `db:"id"`
-	SetNumber      int  `db:"set_number"`
-	MatchId        int  `db:"match_id"`
-	OpponentAScore int  `db:"opp_a_score"`
-	OpponentBScore int  `db:"opp_b_score"`
-	IsCompleted    bool `db:"is_completed"`
+	Id                int       `db:"id"`
+	SetNumber         int       `db:"set_number"`
+	MatchId           int       `db:"match_id"`
+	OpponentAScore    int       `db:"opp_a_score"`
+	OpponentBScore    int       `db:"opp_b_score"`
+	IsCompleted       bool      `db:"is_completed"`
+	StartedAt         time.Time `db:"started_at"`
+	ExpediteFromPoint *int      `db:"expedite_from_point"`
+
+	FirstServerIsPlayerA   *bool `db:"first_server_is_player_a"`
+	FirstReceiverIsPlayerA *bool `db:"first_receiver_is_player_a"`
+	Version                int   `db:"version"`
 }
 
 type Team struct {
-	Id      int    `db:"id"`
-	PlayerA string `db:"player_a"`
-	PlayerB string `db:"player_b"`
+	Id           int  `db:"id"`
+	PlayerAId    int  `db:"player_a_id"`
+	PlayerBId    int  `db:"player_b_id"`
+	TournamentId *int `db:"tournament_id"`
 }
 
 type Player struct {
-	Id   int    `db:"id"`
-	Name string `db:"name"`
+	Id           int    `db:"id"`
+	Name         string `db:"name"`
+	TournamentId *int   `db:"tournament_id"`
+	Club         string `db:"club"`
+	Country      string `db:"country"`
+	Handedness   string `db:"handedness"`
+	GripStyle    string `db:"grip_style"`
+	BirthYear    *int   `db:"birth_year"`
+	Gender       string `db:"gender"`
+	PhotoUrl     string `db:"photo_url"`
 }
 
 type TeamMatchMapping struct {
@@ -44,9 +89,151 @@ type PlayerMatchMapping struct {
 }
 
 type SetLog struct {
+	Id        int       `db:"id"`
+	SetId     int       `db:"set_id"`
+	OppAScore int       `db:"opp_a_score"`
+	OppBScore int       `db:"opp_b_score"`
+	ScoredByA bool      `db:"scored_by_a"`
+	ServedByA bool      `db:"served_by_a"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type MatchEvent struct {
+	Id          int       `db:"id"`
+	MatchId     int       `db:"match_id"`
+	SetId       *int      `db:"set_id"`
+	Type        string    `db:"type"`
+	IsOpponentA *bool     `db:"is_opp_a"`
+	Detail      string    `db:"detail"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type ScoreRedo struct {
 	Id        int  `db:"id"`
-	SetId     int  `db:"set_id"`
-	OppAScore int  `db:"opp_a_score"`
-	OppBScore int  `db:"opp_b_score"`
+	MatchId   int  `db:"match_id"`
+	SetNumber int  `db:"set_number"`
 	ScoredByA bool `db:"scored_by_a"`
 }
+
+type MatchFormatTemplate struct {
+	Id           int    `db:"id"`
+	Name         string `db:"name"`
+	SetCount     int    `db:"set_count"`
+	GamePoint    int    `db:"game_point"`
+	ScoringRules string `db:"scoring_rules"`
+}
+
+type Tournament struct {
+	Id   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type Event struct {
+	Id           int    `db:"id"`
+	TournamentId int    `db:"tournament_id"`
+	Name         string `db:"name"`
+	Format       string `db:"format"`
+}
+
+type MatchGroup struct {
+	Id      int    `db:"id"`
+	EventId int    `db:"event_id"`
+	Name    string `db:"name"`
+}
+
+type MatchGroupEntry struct {
+	GroupId    int `db:"group_id"`
+	OpponentId int `db:"opponent_id"`
+	Position   int `db:"position"`
+}
+
+type Tie struct {
+	Id           int    `db:"id"`
+	EventId      *int   `db:"event_id"`
+	SideA        string `db:"side_a"`
+	SideB        string `db:"side_b"`
+	RubbersToWin int    `db:"rubbers_to_win"`
+	ScoreA       int    `db:"score_a"`
+	ScoreB       int    `db:"score_b"`
+	Status       string `db:"status"`
+	WinnerIsA    *bool  `db:"winner_is_a"`
+}
+
+type EventEntry struct {
+	EventId    int    `db:"event_id"`
+	OpponentId int    `db:"opponent_id"`
+	Seed       int    `db:"seed"`
+	Name       string `db:"name"`
+}
+
+type SwissBye struct {
+	EventId    int `db:"event_id"`
+	Round      int `db:"round"`
+	OpponentId int `db:"opponent_id"`
+}
+
+type Draw struct {
+	Id         int       `db:"id"`
+	EventId    int       `db:"event_id"`
+	RandomSeed int64     `db:"random_seed"`
+	DrawType   string    `db:"draw_type"`
+	Shuffle    string    `db:"shuffle"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type DrawCommitment struct {
+	EventId     int       `db:"event_id"`
+	SeedHash    string    `db:"seed_hash"`
+	CommittedBy string    `db:"committed_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type DrawEntry struct {
+	DrawId     int `db:"draw_id"`
+	OpponentId int `db:"opponent_id"`
+	Seed       int `db:"seed"`
+	EntryOrder int `db:"entry_order"`
+	Slot       int `db:"slot"`
+}
+
+type Season struct {
+	Id           int       `db:"id"`
+	Name         string    `db:"name"`
+	Format       string    `db:"format"`
+	StartsAt     time.Time `db:"starts_at"`
+	WeekDays     int       `db:"week_days"`
+	WalkoverRule string    `db:"walkover_rule"`
+}
+
+type SeasonEntry struct {
+	SeasonId   int `db:"season_id"`
+	OpponentId int `db:"opponent_id"`
+	Position   int `db:"position"`
+}
+
+type FixtureReadiness struct {
+	MatchId    int       `db:"match_id"`
+	OpponentId int       `db:"opponent_id"`
+	ReportedAt time.Time `db:"reported_at"`
+}
+
+// Rating is the Elo rating of a player for SINGLES, or of a pair of players
+// for DOUBLES with the lower player id first. PartnerId is 0 for SINGLES.
+type Rating struct {
+	Format       string  `db:"format"`
+	PlayerId     int     `db:"player_id"`
+	PartnerId    int     `db:"partner_id"`
+	Rating       float64 `db:"rating"`
+	MatchesRated int     `db:"matches_rated"`
+}
+
+type RatingChange struct {
+	Id           int       `db:"id"`
+	MatchId      int       `db:"match_id"`
+	Format       string    `db:"format"`
+	PlayerId     int       `db:"player_id"`
+	PartnerId    int       `db:"partner_id"`
+	RatingBefore float64   `db:"rating_before"`
+	RatingAfter  float64   `db:"rating_after"`
+	CreatedAt    time.Time `db:"created_at"`
+}
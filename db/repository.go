@@ -1,42 +1,176 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// ErrVersionConflict is returned when a row was changed by someone else since
+// it was read.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrUniqueViolation is returned when a row would duplicate a value that must
+// be unique.
+var ErrUniqueViolation = errors.New("unique violation")
+
 type Repository interface {
+	RunInTx(fn func(repo Repository) error) error
+	LockMatchById(id int) (*Match, error)
 	CreateMatch(match *Match) (int64, error)
-	CreatePlayer(player *Player) error
+	CreatePlayer(player *Player) (int64, error)
 	CreateTeam(team *Team) error
+	GetTeamById(id int) (*Team, error)
+	GetPlayerById(id int) (*Player, error)
+	GetPlayersByName(name string) ([]Player, error)
+	SearchPlayers(name string, tournamentId *int) ([]Player, error)
+	UpdatePlayer(player *Player) error
+	DeletePlayer(id int) error
+	IsPlayerInUse(id int) (bool, error)
 	CreateSet(set *Set) (int64, error)
 	UpdateSet(set *Set) error
 	AddTeamToMatch(mapping *TeamMatchMapping) error
 	AddPlayerToMatch(mapping *PlayerMatchMapping) error
 	UpdateMatchWinner(match *Match, isOppA bool) error
 	ResetMatchWinner(match *Match) error
-	GetAllMatches(matches *[]Match, statusFilter string) error
+	RemoveOpponentFromMatch(match *Match, isOppA bool) error
+	GetAllMatches(matches *[]Match, statusFilter string, tournamentId *int, eventId *int) error
 	GetMatchById(id int) (*Match, error)
 	GetSetsByMatchId(id int) ([]Set, error)
 	GetTeamInfoByMatchId(matchId int) ([]TeamInfoByMatchIdRow, error)
 	GetPlayerInfoByMatchId(matchId int) ([]PlayerInfoByMatchIdRow, error)
 	UpdateMatchStatus(matchId int, status string) error
+	UpdateMatchFirstServer(matchId int, firstServerIsA bool) error
+	UpdateMatchResult(matchId int, result string) error
 	CreateSetLog(setLog *SetLog) error
 	DeleteSetLog(id int) error
 	GetSetLogsBySetId(setId int, limit *int) ([]SetLog, error)
+	CreateMatchEvent(event *MatchEvent) error
+	GetMatchEventsByMatchId(matchId int) ([]MatchEvent, error)
+	DeleteMatchEventsBySetId(setId int, eventType *string) error
+	MoveMatchEventsToSet(fromSetId int, toSetId int) error
+	DeleteSet(id int) error
+	PushScoreRedo(redo *ScoreRedo) error
+	PopScoreRedo(matchId int) (*ScoreRedo, error)
+	ClearScoreRedos(matchId int) error
+	CreateMatchFormatTemplate(template *MatchFormatTemplate) error
+	GetAllMatchFormatTemplates() ([]MatchFormatTemplate, error)
+	GetMatchFormatTemplateByName(name string) (*MatchFormatTemplate, error)
+	UpdateMatchFormatTemplate(template *MatchFormatTemplate) error
+	DeleteMatchFormatTemplate(name string) error
+	CreateTournament(tournament *Tournament) (int64, error)
+	GetAllTournaments() ([]Tournament, error)
+	GetTournamentById(id int) (*Tournament, error)
+	CreateEvent(event *Event) (int64, error)
+	GetEventsByTournamentId(tournamentId int) ([]Event, error)
+	GetEventById(id int) (*Event, error)
+	CreateMatchGroup(group *MatchGroup) (int64, error)
+	GetMatchGroupById(id int) (*MatchGroup, error)
+	GetMatchGroupsByEventId(eventId int) ([]MatchGroup, error)
+	AddEntryToMatchGroup(entry *MatchGroupEntry) error
+	GetMatchGroupEntries(groupId int) ([]MatchGroupEntry, error)
+	GetMatchesByGroupId(groupId int) ([]Match, error)
+	UpdateMatchGroup(matchId int, groupId *int) error
+	CreateTie(tie *Tie) (int64, error)
+	GetTieById(id int) (*Tie, error)
+	LockTieById(id int) (*Tie, error)
+	UpdateTie(tie *Tie) error
+	GetMatchesByTieId(tieId int) ([]Match, error)
+	AddEventEntry(entry *EventEntry) error
+	GetEventEntries(eventId int) ([]EventEntry, error)
+	CreateSwissBye(bye *SwissBye) error
+	GetSwissByes(eventId int) ([]SwissBye, error)
+	CreateDraw(draw *Draw) (int64, error)
+	AddDrawEntry(entry *DrawEntry) error
+	GetDrawByEventId(eventId int) (*Draw, error)
+	GetDrawEntries(drawId int) ([]DrawEntry, error)
+	CreateDrawCommitment(commitment *DrawCommitment) error
+	GetDrawCommitment(eventId int) (*DrawCommitment, error)
+	CreateSeason(season *Season) (int64, error)
+	GetAllSeasons() ([]Season, error)
+	GetSeasonById(id int) (*Season, error)
+	AddSeasonEntry(entry *SeasonEntry) error
+	GetSeasonEntries(seasonId int) ([]SeasonEntry, error)
+	GetMatchesBySeasonId(seasonId int) ([]Match, error)
+	GetOverdueSeasonMatches(now time.Time) ([]Match, error)
+	ReportFixtureReady(readiness *FixtureReadiness) error
+	GetFixtureReadiness(matchId int) ([]FixtureReadiness, error)
+	LockRating(format string, playerId int, partnerId int, initialRating float64) (*Rating, error)
+	UpdateRating(rating *Rating) error
+	CreateRatingChange(change *RatingChange) error
+	GetRatingChangesByMatchId(matchId int) ([]RatingChange, error)
+	DeleteRatingChangesByMatchId(matchId int) error
+	GetRatingChangesByPlayer(format string, playerId int, partnerId int) ([]RatingChange, error)
+	GetRankings(format string) ([]RankingRow, error)
+}
+
+// dbtx is implemented by both *sqlx.DB and *sqlx.Tx.
+type dbtx interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	NamedExec(query string, arg interface{}) (sql.Result, error)
+	NamedQuery(query string, arg interface{}) (*sqlx.Rows, error)
+	PrepareNamed(query string) (*sqlx.NamedStmt, error)
 }
 
 type repository struct {
-	db *sqlx.DB
+	db   dbtx
+	conn *sqlx.DB
 }
 
 func NewRepository(db *sqlx.DB) Repository {
-	return &repository{db: db}
+	return &repository{db: db, conn: db}
+}
+
+// RunInTx runs fn with a repository bound to a single transaction, which is
+// committed when fn succeeds and rolled back otherwise. Calls made on a
+// repository that is already bound to a transaction join it.
+func (r *repository) RunInTx(fn func(repo Repository) error) (err error) {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	tx, err := r.conn.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(&repository{db: tx})
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *repository) CreateMatch(match *Match) (int64, error) {
 	query := `
-		INSERT INTO match (stage, format, game_point, set_count, status)
-		VALUES (:stage, :format, :game_point, :set_count, :status)
+		INSERT INTO match (
+			stage, format, game_point, set_count, status, first_server_is_a, scoring_rules,
+			handicap_a, handicap_b, format_template, event_id,
+			bracket_round, bracket_position, next_match_id, next_match_slot_is_a,
+			group_id, group_round, tie_id, rubber_number,
+			bracket, loser_next_match_id, loser_next_match_slot_is_a, swiss_round,
+			season_id, match_week, deadline
+		)
+		VALUES (
+			:stage, :format, :game_point, :set_count, :status, :first_server_is_a, :scoring_rules,
+			:handicap_a, :handicap_b, :format_template, :event_id,
+			:bracket_round, :bracket_position, :next_match_id, :next_match_slot_is_a,
+			:group_id, :group_round, :tie_id, :rubber_number,
+			:bracket, :loser_next_match_id, :loser_next_match_slot_is_a, :swiss_round,
+			:season_id, :match_week, :deadline
+		)
 		RETURNING id;
 	`
 
@@ -47,6 +181,7 @@ func (r *repository) CreateMatch(match *Match) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	defer rows.Close()
 	rows.Next()
 	err = rows.Scan(&id)
 
@@ -60,8 +195,14 @@ func (r *repository) CreateMatch(match *Match) (int64, error) {
 
 func (r *repository) CreateSet(set *Set) (int64, error) {
 	query := `
-		INSERT INTO set (set_number, match_id, opp_a_score, opp_b_score, is_completed)
-		VALUES (:set_number, :match_id, :opp_a_score, :opp_b_score, :is_completed)
+		INSERT INTO set (
+			set_number, match_id, opp_a_score, opp_b_score, is_completed, started_at, expedite_from_point,
+			first_server_is_player_a, first_receiver_is_player_a
+		)
+		VALUES (
+			:set_number, :match_id, :opp_a_score, :opp_b_score, :is_completed, :started_at, :expedite_from_point,
+			:first_server_is_player_a, :first_receiver_is_player_a
+		)
 		RETURNING id;
 	`
 
@@ -72,6 +213,7 @@ func (r *repository) CreateSet(set *Set) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	defer rows.Close()
 	rows.Next()
 	err = rows.Scan(&id)
 
@@ -87,13 +229,27 @@ func (r *repository) UpdateSet(set *Set) error {
 		UPDATE set 
 		SET set_number = :set_number, match_id = :match_id,
 		opp_a_score = :opp_a_score, opp_b_score = :opp_b_score,
-		is_completed = :is_completed
-		WHERE id = :id;
+		is_completed = :is_completed, expedite_from_point = :expedite_from_point,
+		first_server_is_player_a = :first_server_is_player_a,
+		first_receiver_is_player_a = :first_receiver_is_player_a,
+		version = version + 1
+		WHERE id = :id AND version = :version;
 	`
 
-	_, err := r.db.NamedExec(query, set)
+	result, err := r.db.NamedExec(query, set)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrVersionConflict
+	}
+	set.Version += 1
 
-	return err
+	return nil
 }
 
 func (r *repository) GetMatchById(id int) (*Match, error) {
@@ -109,6 +265,19 @@ func (r *repository) GetMatchById(id int) (*Match, error) {
 	return &match, nil
 }
 
+func (r *repository) LockMatchById(id int) (*Match, error) {
+	query := `
+		SELECT * FROM match WHERE id = $1 FOR UPDATE;
+	`
+	var match Match
+	err := r.db.Get(&match, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &match, nil
+}
+
 func (r *repository) GetSetsByMatchId(id int) ([]Set, error) {
 	query := `
 		SELECT * FROM set WHERE match_id = :id ORDER BY set_number ASC;
@@ -118,6 +287,7 @@ func (r *repository) GetSetsByMatchId(id int) ([]Set, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	var sets []Set
 	for rows.Next() {
 		var set Set
@@ -131,25 +301,123 @@ func (r *repository) GetSetsByMatchId(id int) ([]Set, error) {
 	return sets, nil
 }
 
-func (r *repository) CreatePlayer(player *Player) error {
+func (r *repository) CreatePlayer(player *Player) (int64, error) {
 	query := `
-		INSERT INTO player (name)
-		VALUES (:name)
-		RETURNING id
+		INSERT INTO player (name, tournament_id, club, country, handedness, grip_style, birth_year, gender, photo_url)
+		VALUES (:name, :tournament_id, :club, :country, :handedness, :grip_style, :birth_year, :gender, :photo_url)
+		RETURNING id;
 	`
 
-	_, err := r.db.NamedExec(query, player)
+	var id int64
+	rows, err := r.db.NamedQuery(query, player)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
 	}
 
-	return nil
+	return id, nil
+}
+
+func (r *repository) GetPlayerById(id int) (*Player, error) {
+	query := `
+		SELECT * FROM player WHERE id = $1;
+	`
+	var player Player
+	err := r.db.Get(&player, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+func (r *repository) GetPlayersByName(name string) ([]Player, error) {
+	query := `SELECT * FROM player WHERE name = $1 ORDER BY id ASC`
+
+	players := []Player{}
+
+	if err := r.db.Select(&players, query, name); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
+
+// SearchPlayers lists the players whose name contains the given text, ignoring
+// case, limited to the players of the tournament when tournamentId is set.
+func (r *repository) SearchPlayers(name string, tournamentId *int) ([]Player, error) {
+	query := `SELECT * FROM player WHERE strpos(lower(name), lower($1)) > 0`
+	args := []interface{}{name}
+	if tournamentId != nil {
+		query += ` AND tournament_id = $2`
+		args = append(args, *tournamentId)
+	}
+	query += ` ORDER BY lower(name) ASC, id ASC`
+
+	players := []Player{}
+
+	if err := r.db.Select(&players, query, args...); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
+
+func (r *repository) UpdatePlayer(player *Player) error {
+	query := `
+		UPDATE player
+		SET name = :name, club = :club, country = :country, handedness = :handedness, grip_style = :grip_style,
+			birth_year = :birth_year, gender = :gender, photo_url = :photo_url
+		WHERE id = :id;
+	`
+
+	_, err := r.db.NamedExec(query, player)
+
+	return err
+}
+
+func (r *repository) DeletePlayer(id int) error {
+	query := `
+		DELETE FROM player WHERE id = :id;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"id": id})
+
+	return err
+}
+
+// IsPlayerInUse reports whether the player has played a match or is part of
+// a team.
+func (r *repository) IsPlayerInUse(id int) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM player_match_mapping WHERE player_id = $1)
+			OR EXISTS (SELECT 1 FROM team WHERE player_a_id = $1 OR player_b_id = $1)
+			OR EXISTS (
+				SELECT 1 FROM event_entry JOIN event ON event_entry.event_id = event.id
+				WHERE event.format <> 'DOUBLES' AND event_entry.opponent_id = $1
+			)
+			OR EXISTS (
+				SELECT 1 FROM season_entry JOIN season ON season_entry.season_id = season.id
+				WHERE season.format <> 'DOUBLES' AND season_entry.opponent_id = $1
+			);
+	`
+
+	var inUse bool
+	if err := r.db.Get(&inUse, query, id); err != nil {
+		return false, err
+	}
+
+	return inUse, nil
 }
 
 func (r *repository) CreateTeam(team *Team) error {
 	query := `
-		INSERT INTO team (player_a, player_b)
-		VALUES (:player_a, :player_b)
+		INSERT INTO team (player_a_id, player_b_id, tournament_id)
+		VALUES (:player_a_id, :player_b_id, :tournament_id)
 		RETURNING id
 	`
 	_, err := r.db.NamedExec(query, team)
@@ -159,6 +427,19 @@ func (r *repository) CreateTeam(team *Team) error {
 	return nil
 }
 
+func (r *repository) GetTeamById(id int) (*Team, error) {
+	query := `
+		SELECT id, player_a_id, player_b_id, tournament_id FROM team WHERE id = $1;
+	`
+	var team Team
+	err := r.db.Get(&team, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
 func (r *repository) AddTeamToMatch(mapping *TeamMatchMapping) error {
 	query := `
 		INSERT INTO team_match_mapping (match_id, team_id, is_opp_a, is_winner)
@@ -195,6 +476,26 @@ func (r *repository) ResetMatchWinner(match *Match) error {
 	return r.writeMatchWinnerStatus(match, false, false)
 }
 
+func (r *repository) RemoveOpponentFromMatch(match *Match, isOppA bool) error {
+	query := ``
+	if match.Format == "SINGLES" {
+		query = `
+			DELETE FROM player_match_mapping
+			WHERE match_id = :match_id AND is_opp_a = :is_opp_a
+		`
+	} else {
+		query = `
+			DELETE FROM team_match_mapping
+			WHERE match_id = :match_id AND is_opp_a = :is_opp_a
+		`
+	}
+	_, err := r.db.NamedExec(
+		query,
+		map[string]interface{}{"match_id": match.Id, "is_opp_a": isOppA},
+	)
+	return err
+}
+
 func (r *repository) writeMatchWinnerStatus(match *Match, isOppA bool, isWinner bool) error {
 	query := ``
 	if match.Format == "SINGLES" {
@@ -218,14 +519,26 @@ func (r *repository) writeMatchWinnerStatus(match *Match, isOppA bool, isWinner
 	return nil
 }
 
-func (r *repository) GetAllMatches(matches *[]Match, statusFilter string) error {
-	query := `SELECT * FROM match`
+// GetAllMatches loads the matches with the given status, tournament and event.
+// Empty or nil filters are not applied.
+func (r *repository) GetAllMatches(matches *[]Match, statusFilter string, tournamentId *int, eventId *int) error {
+	query := `SELECT match.* FROM match LEFT JOIN event ON match.event_id = event.id`
 
+	conditions := []string{}
 	if statusFilter != "" {
-		query += ` WHERE status = :filter`
+		conditions = append(conditions, `match.status = :filter`)
+	}
+	if tournamentId != nil {
+		conditions = append(conditions, `event.tournament_id = :tournamentId`)
+	}
+	if eventId != nil {
+		conditions = append(conditions, `match.event_id = :eventId`)
+	}
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
 	}
 
-	query += ` ORDER BY id ASC`
+	query += ` ORDER BY match.id ASC`
 
 	stmt, err := r.db.PrepareNamed(query)
 	if err != nil {
@@ -233,7 +546,8 @@ func (r *repository) GetAllMatches(matches *[]Match, statusFilter string) error
 	}
 	defer stmt.Close()
 
-	if err := stmt.Select(matches, map[string]interface{}{"filter": statusFilter}); err != nil {
+	args := map[string]interface{}{"filter": statusFilter, "tournamentId": tournamentId, "eventId": eventId}
+	if err := stmt.Select(matches, args); err != nil {
 		return err
 	}
 
@@ -241,17 +555,77 @@ func (r *repository) GetAllMatches(matches *[]Match, statusFilter string) error
 }
 
 type TeamInfoByMatchIdRow struct {
-	MatchId     int    `db:"match_id"`
-	Id          int    `db:"id"`
-	TeamId      int    `db:"team_id"`
-	PlayerA     string `db:"player_a"`
-	PlayerB     string `db:"player_b"`
-	IsOpponentA bool   `db:"is_opp_a"`
-	IsWinner    bool   `db:"is_winner"`
+	MatchId      int    `db:"match_id"`
+	Id           int    `db:"id"`
+	TeamId       int    `db:"team_id"`
+	PlayerAId    int    `db:"player_a_id"`
+	PlayerBId    int    `db:"player_b_id"`
+	PlayerA      string `db:"player_a"`
+	PlayerB      string `db:"player_b"`
+	TournamentId *int   `db:"tournament_id"`
+	IsOpponentA  bool   `db:"is_opp_a"`
+	IsWinner     bool   `db:"is_winner"`
+
+	PlayerATournamentId *int   `db:"player_a_tournament_id"`
+	PlayerAClub         string `db:"player_a_club"`
+	PlayerACountry      string `db:"player_a_country"`
+	PlayerAHandedness   string `db:"player_a_handedness"`
+	PlayerAGripStyle    string `db:"player_a_grip_style"`
+	PlayerABirthYear    *int   `db:"player_a_birth_year"`
+	PlayerAGender       string `db:"player_a_gender"`
+	PlayerAPhotoUrl     string `db:"player_a_photo_url"`
+	PlayerBTournamentId *int   `db:"player_b_tournament_id"`
+	PlayerBClub         string `db:"player_b_club"`
+	PlayerBCountry      string `db:"player_b_country"`
+	PlayerBHandedness   string `db:"player_b_handedness"`
+	PlayerBGripStyle    string `db:"player_b_grip_style"`
+	PlayerBBirthYear    *int   `db:"player_b_birth_year"`
+	PlayerBGender       string `db:"player_b_gender"`
+	PlayerBPhotoUrl     string `db:"player_b_photo_url"`
+}
+
+// Players returns the profiles of the two players of the team.
+func (row TeamInfoByMatchIdRow) Players() [2]Player {
+	return [2]Player{
+		{
+			Id:           row.PlayerAId,
+			Name:         row.PlayerA,
+			TournamentId: row.PlayerATournamentId,
+			Club:         row.PlayerAClub,
+			Country:      row.PlayerACountry,
+			Handedness:   row.PlayerAHandedness,
+			GripStyle:    row.PlayerAGripStyle,
+			BirthYear:    row.PlayerABirthYear,
+			Gender:       row.PlayerAGender,
+			PhotoUrl:     row.PlayerAPhotoUrl,
+		},
+		{
+			Id:           row.PlayerBId,
+			Name:         row.PlayerB,
+			TournamentId: row.PlayerBTournamentId,
+			Club:         row.PlayerBClub,
+			Country:      row.PlayerBCountry,
+			Handedness:   row.PlayerBHandedness,
+			GripStyle:    row.PlayerBGripStyle,
+			BirthYear:    row.PlayerBBirthYear,
+			Gender:       row.PlayerBGender,
+			PhotoUrl:     row.PlayerBPhotoUrl,
+		},
+	}
 }
 
 func (r *repository) GetTeamInfoByMatchId(matchId int) ([]TeamInfoByMatchIdRow, error) {
-	query := `SELECT * FROM team_match_mapping JOIN team ON team_match_mapping.team_id = team.id`
+	query := `SELECT team_match_mapping.*, team.id, team.player_a_id, team.player_b_id, team.tournament_id,`
+	query += ` player_a.name AS player_a, player_b.name AS player_b`
+	query += `, player_a.tournament_id AS player_a_tournament_id, player_a.club AS player_a_club, player_a.country AS player_a_country`
+	query += `, player_a.handedness AS player_a_handedness, player_a.grip_style AS player_a_grip_style, player_a.birth_year AS player_a_birth_year`
+	query += `, player_a.gender AS player_a_gender, player_a.photo_url AS player_a_photo_url`
+	query += `, player_b.tournament_id AS player_b_tournament_id, player_b.club AS player_b_club, player_b.country AS player_b_country`
+	query += `, player_b.handedness AS player_b_handedness, player_b.grip_style AS player_b_grip_style, player_b.birth_year AS player_b_birth_year`
+	query += `, player_b.gender AS player_b_gender, player_b.photo_url AS player_b_photo_url`
+	query += ` FROM team_match_mapping JOIN team ON team_match_mapping.team_id = team.id`
+	query += ` JOIN player AS player_a ON team.player_a_id = player_a.id`
+	query += ` JOIN player AS player_b ON team.player_b_id = player_b.id`
 	query += ` WHERE match_id = :matchId`
 	query += ` ORDER BY is_opp_a DESC`
 
@@ -271,12 +645,20 @@ func (r *repository) GetTeamInfoByMatchId(matchId int) ([]TeamInfoByMatchIdRow,
 }
 
 type PlayerInfoByMatchIdRow struct {
-	MatchId     int    `db:"match_id"`
-	Id          int    `db:"id"`
-	PlayerId    int    `db:"player_id"`
-	PlayerName  string `db:"name"`
-	IsOpponentA bool   `db:"is_opp_a"`
-	IsWinner    bool   `db:"is_winner"`
+	MatchId      int    `db:"match_id"`
+	Id           int    `db:"id"`
+	PlayerId     int    `db:"player_id"`
+	PlayerName   string `db:"name"`
+	TournamentId *int   `db:"tournament_id"`
+	Club         string `db:"club"`
+	Country      string `db:"country"`
+	Handedness   string `db:"handedness"`
+	GripStyle    string `db:"grip_style"`
+	BirthYear    *int   `db:"birth_year"`
+	Gender       string `db:"gender"`
+	PhotoUrl     string `db:"photo_url"`
+	IsOpponentA  bool   `db:"is_opp_a"`
+	IsWinner     bool   `db:"is_winner"`
 }
 
 func (r *repository) GetPlayerInfoByMatchId(matchId int) ([]PlayerInfoByMatchIdRow, error) {
@@ -313,10 +695,38 @@ func (r *repository) UpdateMatchStatus(matchId int, status string) error {
 	return err
 }
 
+func (r *repository) UpdateMatchFirstServer(matchId int, firstServerIsA bool) error {
+	query := `UPDATE match SET first_server_is_a = :firstServerIsA WHERE id = :matchId`
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(map[string]interface{}{"matchId": matchId, "firstServerIsA": firstServerIsA})
+
+	return err
+}
+
+func (r *repository) UpdateMatchResult(matchId int, result string) error {
+	query := `UPDATE match SET result = :result WHERE id = :matchId`
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(map[string]interface{}{"matchId": matchId, "result": result})
+
+	return err
+}
+
 func (r *repository) CreateSetLog(setLog *SetLog) error {
 	query := `
-		INSERT INTO set_log (set_id, opp_a_score, opp_b_score, scored_by_a)
-		VALUES (:set_id, :opp_a_score, :opp_b_score, :scored_by_a);
+		INSERT INTO set_log (set_id, opp_a_score, opp_b_score, scored_by_a, served_by_a)
+		VALUES (:set_id, :opp_a_score, :opp_b_score, :scored_by_a, :served_by_a);
 	`
 
 	_, err := r.db.NamedExec(query, setLog)
@@ -363,3 +773,816 @@ func (r *repository) GetSetLogsBySetId(setId int, limit *int) ([]SetLog, error)
 
 	return setLogs, nil
 }
+
+func (r *repository) CreateMatchEvent(event *MatchEvent) error {
+	query := `
+		INSERT INTO match_event (match_id, set_id, type, is_opp_a, detail)
+		VALUES (:match_id, :set_id, :type, :is_opp_a, :detail);
+	`
+
+	_, err := r.db.NamedExec(query, event)
+
+	return err
+}
+
+func (r *repository) GetMatchEventsByMatchId(matchId int) ([]MatchEvent, error) {
+	query := `SELECT * FROM match_event WHERE match_id = :matchId ORDER BY id ASC`
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	events := []MatchEvent{}
+
+	if err := stmt.Select(&events, map[string]interface{}{"matchId": matchId}); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *repository) DeleteMatchEventsBySetId(setId int, eventType *string) error {
+	query := `DELETE FROM match_event WHERE set_id = :setId`
+	if eventType != nil {
+		query += ` AND type = :type`
+	}
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"setId": setId, "type": eventType})
+
+	return err
+}
+
+func (r *repository) MoveMatchEventsToSet(fromSetId int, toSetId int) error {
+	query := `
+		UPDATE match_event SET set_id = :toSetId WHERE set_id = :fromSetId;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"fromSetId": fromSetId, "toSetId": toSetId})
+
+	return err
+}
+
+func (r *repository) DeleteSet(id int) error {
+	query := `
+		DELETE FROM set WHERE id = :id;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"id": id})
+
+	return err
+}
+
+func (r *repository) PushScoreRedo(redo *ScoreRedo) error {
+	query := `
+		INSERT INTO score_redo (match_id, set_number, scored_by_a)
+		VALUES (:match_id, :set_number, :scored_by_a);
+	`
+
+	_, err := r.db.NamedExec(query, redo)
+
+	return err
+}
+
+// PopScoreRedo removes and returns the most recently pushed redo of the match,
+// or nil when there is nothing to redo.
+func (r *repository) PopScoreRedo(matchId int) (*ScoreRedo, error) {
+	query := `
+		DELETE FROM score_redo WHERE id = (
+			SELECT id FROM score_redo WHERE match_id = $1 ORDER BY id DESC LIMIT 1
+		)
+		RETURNING *;
+	`
+	var redo ScoreRedo
+	err := r.db.Get(&redo, query, matchId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &redo, nil
+}
+
+func (r *repository) ClearScoreRedos(matchId int) error {
+	query := `
+		DELETE FROM score_redo WHERE match_id = :matchId;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"matchId": matchId})
+
+	return err
+}
+
+func (r *repository) CreateMatchFormatTemplate(template *MatchFormatTemplate) error {
+	query := `
+		INSERT INTO match_format_template (name, set_count, game_point, scoring_rules)
+		VALUES (:name, :set_count, :game_point, :scoring_rules);
+	`
+
+	_, err := r.db.NamedExec(query, template)
+
+	return err
+}
+
+func (r *repository) GetAllMatchFormatTemplates() ([]MatchFormatTemplate, error) {
+	query := `SELECT * FROM match_format_template ORDER BY name ASC`
+
+	templates := []MatchFormatTemplate{}
+
+	if err := r.db.Select(&templates, query); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *repository) GetMatchFormatTemplateByName(name string) (*MatchFormatTemplate, error) {
+	query := `
+		SELECT * FROM match_format_template WHERE name = $1;
+	`
+	var template MatchFormatTemplate
+	err := r.db.Get(&template, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *repository) UpdateMatchFormatTemplate(template *MatchFormatTemplate) error {
+	query := `
+		UPDATE match_format_template
+		SET set_count = :set_count, game_point = :game_point, scoring_rules = :scoring_rules
+		WHERE name = :name;
+	`
+
+	_, err := r.db.NamedExec(query, template)
+
+	return err
+}
+
+func (r *repository) DeleteMatchFormatTemplate(name string) error {
+	query := `
+		DELETE FROM match_format_template WHERE name = :name;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"name": name})
+
+	return err
+}
+
+func (r *repository) CreateTournament(tournament *Tournament) (int64, error) {
+	query := `
+		INSERT INTO tournament (name)
+		VALUES (:name)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, tournament)
+	if err != nil {
+		return 0, uniqueViolation(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return 0, uniqueViolation(rows.Err())
+	}
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// uniqueViolation reports a statement that broke a unique constraint as
+// ErrUniqueViolation.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrUniqueViolation
+	}
+	return err
+}
+
+func (r *repository) GetAllTournaments() ([]Tournament, error) {
+	query := `SELECT * FROM tournament ORDER BY id ASC`
+
+	tournaments := []Tournament{}
+
+	if err := r.db.Select(&tournaments, query); err != nil {
+		return nil, err
+	}
+
+	return tournaments, nil
+}
+
+func (r *repository) GetTournamentById(id int) (*Tournament, error) {
+	query := `
+		SELECT * FROM tournament WHERE id = $1;
+	`
+	var tournament Tournament
+	err := r.db.Get(&tournament, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tournament, nil
+}
+
+func (r *repository) CreateEvent(event *Event) (int64, error) {
+	query := `
+		INSERT INTO event (tournament_id, name, format)
+		VALUES (:tournament_id, :name, :format)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, event)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) GetEventsByTournamentId(tournamentId int) ([]Event, error) {
+	query := `SELECT * FROM event WHERE tournament_id = $1 ORDER BY id ASC`
+
+	events := []Event{}
+
+	if err := r.db.Select(&events, query, tournamentId); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *repository) GetEventById(id int) (*Event, error) {
+	query := `
+		SELECT * FROM event WHERE id = $1;
+	`
+	var event Event
+	err := r.db.Get(&event, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (r *repository) CreateMatchGroup(group *MatchGroup) (int64, error) {
+	query := `
+		INSERT INTO match_group (event_id, name)
+		VALUES (:event_id, :name)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, group)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) GetMatchGroupById(id int) (*MatchGroup, error) {
+	query := `
+		SELECT * FROM match_group WHERE id = $1;
+	`
+	var group MatchGroup
+	err := r.db.Get(&group, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *repository) GetMatchGroupsByEventId(eventId int) ([]MatchGroup, error) {
+	query := `SELECT * FROM match_group WHERE event_id = $1 ORDER BY name ASC`
+
+	groups := []MatchGroup{}
+
+	if err := r.db.Select(&groups, query, eventId); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (r *repository) AddEntryToMatchGroup(entry *MatchGroupEntry) error {
+	query := `
+		INSERT INTO match_group_entry (group_id, opponent_id, position)
+		VALUES (:group_id, :opponent_id, :position);
+	`
+
+	_, err := r.db.NamedExec(query, entry)
+
+	return err
+}
+
+func (r *repository) GetMatchGroupEntries(groupId int) ([]MatchGroupEntry, error) {
+	query := `SELECT * FROM match_group_entry WHERE group_id = $1 ORDER BY position ASC`
+
+	entries := []MatchGroupEntry{}
+
+	if err := r.db.Select(&entries, query, groupId); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *repository) GetMatchesByGroupId(groupId int) ([]Match, error) {
+	query := `SELECT * FROM match WHERE group_id = $1 ORDER BY group_round ASC, id ASC`
+
+	matches := []Match{}
+
+	if err := r.db.Select(&matches, query, groupId); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *repository) UpdateMatchGroup(matchId int, groupId *int) error {
+	query := `
+		UPDATE match SET group_id = :groupId WHERE id = :matchId;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"matchId": matchId, "groupId": groupId})
+
+	return err
+}
+
+func (r *repository) CreateTie(tie *Tie) (int64, error) {
+	query := `
+		INSERT INTO tie (event_id, side_a, side_b, rubbers_to_win, score_a, score_b, status, winner_is_a)
+		VALUES (:event_id, :side_a, :side_b, :rubbers_to_win, :score_a, :score_b, :status, :winner_is_a)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, tie)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) GetTieById(id int) (*Tie, error) {
+	query := `
+		SELECT * FROM tie WHERE id = $1;
+	`
+	var tie Tie
+	err := r.db.Get(&tie, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tie, nil
+}
+
+func (r *repository) LockTieById(id int) (*Tie, error) {
+	query := `
+		SELECT * FROM tie WHERE id = $1 FOR UPDATE;
+	`
+	var tie Tie
+	err := r.db.Get(&tie, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tie, nil
+}
+
+func (r *repository) UpdateTie(tie *Tie) error {
+	query := `
+		UPDATE tie
+		SET score_a = :score_a, score_b = :score_b, status = :status, winner_is_a = :winner_is_a
+		WHERE id = :id;
+	`
+
+	_, err := r.db.NamedExec(query, tie)
+
+	return err
+}
+
+func (r *repository) GetMatchesByTieId(tieId int) ([]Match, error) {
+	query := `SELECT * FROM match WHERE tie_id = $1 ORDER BY rubber_number ASC`
+
+	matches := []Match{}
+
+	if err := r.db.Select(&matches, query, tieId); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *repository) AddEventEntry(entry *EventEntry) error {
+	query := `
+		INSERT INTO event_entry (event_id, opponent_id, seed)
+		VALUES (:event_id, :opponent_id, :seed)
+		ON CONFLICT (event_id, opponent_id) DO UPDATE SET seed = :seed;
+	`
+
+	_, err := r.db.NamedExec(query, entry)
+
+	return err
+}
+
+// GetEventEntries loads the entries of the event with the name of the player
+// or team, ordered by seed with unseeded entries last.
+func (r *repository) GetEventEntries(eventId int) ([]EventEntry, error) {
+	query := `
+		SELECT event_entry.event_id, event_entry.opponent_id, event_entry.seed,
+			CASE WHEN event.format = 'DOUBLES'
+				THEN COALESCE(team_player_a.name || ' & ' || team_player_b.name, '')
+				ELSE COALESCE(player.name, '')
+			END AS name
+		FROM event_entry
+		JOIN event ON event_entry.event_id = event.id
+		LEFT JOIN player ON event.format <> 'DOUBLES' AND player.id = event_entry.opponent_id
+		LEFT JOIN team ON event.format = 'DOUBLES' AND team.id = event_entry.opponent_id
+		LEFT JOIN player AS team_player_a ON team.player_a_id = team_player_a.id
+		LEFT JOIN player AS team_player_b ON team.player_b_id = team_player_b.id
+		WHERE event_entry.event_id = $1
+		ORDER BY event_entry.seed = 0, event_entry.seed ASC, event_entry.opponent_id ASC
+	`
+
+	entries := []EventEntry{}
+
+	if err := r.db.Select(&entries, query, eventId); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *repository) CreateSwissBye(bye *SwissBye) error {
+	query := `
+		INSERT INTO swiss_bye (event_id, round, opponent_id)
+		VALUES (:event_id, :round, :opponent_id);
+	`
+
+	_, err := r.db.NamedExec(query, bye)
+
+	return err
+}
+
+func (r *repository) GetSwissByes(eventId int) ([]SwissBye, error) {
+	query := `SELECT * FROM swiss_bye WHERE event_id = $1 ORDER BY round ASC`
+
+	byes := []SwissBye{}
+
+	if err := r.db.Select(&byes, query, eventId); err != nil {
+		return nil, err
+	}
+
+	return byes, nil
+}
+
+func (r *repository) CreateDraw(draw *Draw) (int64, error) {
+	query := `
+		INSERT INTO draw (event_id, random_seed, draw_type, shuffle)
+		VALUES (:event_id, :random_seed, :draw_type, :shuffle)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, draw)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) AddDrawEntry(entry *DrawEntry) error {
+	query := `
+		INSERT INTO draw_entry (draw_id, opponent_id, seed, entry_order, slot)
+		VALUES (:draw_id, :opponent_id, :seed, :entry_order, :slot);
+	`
+
+	_, err := r.db.NamedExec(query, entry)
+
+	return err
+}
+
+func (r *repository) GetDrawByEventId(eventId int) (*Draw, error) {
+	query := `
+		SELECT * FROM draw WHERE event_id = $1;
+	`
+	var draw Draw
+	err := r.db.Get(&draw, query, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &draw, nil
+}
+
+func (r *repository) GetDrawEntries(drawId int) ([]DrawEntry, error) {
+	query := `SELECT * FROM draw_entry WHERE draw_id = $1 ORDER BY entry_order ASC`
+
+	entries := []DrawEntry{}
+
+	if err := r.db.Select(&entries, query, drawId); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *repository) CreateDrawCommitment(commitment *DrawCommitment) error {
+	query := `
+		INSERT INTO draw_commitment (event_id, seed_hash, committed_by)
+		VALUES (:event_id, :seed_hash, :committed_by);
+	`
+
+	_, err := r.db.NamedExec(query, commitment)
+
+	return uniqueViolation(err)
+}
+
+func (r *repository) GetDrawCommitment(eventId int) (*DrawCommitment, error) {
+	query := `
+		SELECT * FROM draw_commitment WHERE event_id = $1;
+	`
+	var commitment DrawCommitment
+	err := r.db.Get(&commitment, query, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commitment, nil
+}
+
+func (r *repository) CreateSeason(season *Season) (int64, error) {
+	query := `
+		INSERT INTO season (name, format, starts_at, week_days, walkover_rule)
+		VALUES (:name, :format, :starts_at, :week_days, :walkover_rule)
+		RETURNING id;
+	`
+
+	var id int64
+	rows, err := r.db.NamedQuery(query, season)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	rows.Next()
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) GetAllSeasons() ([]Season, error) {
+	query := `SELECT * FROM season ORDER BY starts_at DESC, id DESC`
+
+	seasons := []Season{}
+
+	if err := r.db.Select(&seasons, query); err != nil {
+		return nil, err
+	}
+
+	return seasons, nil
+}
+
+func (r *repository) GetSeasonById(id int) (*Season, error) {
+	query := `
+		SELECT * FROM season WHERE id = $1;
+	`
+	var season Season
+	err := r.db.Get(&season, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &season, nil
+}
+
+func (r *repository) AddSeasonEntry(entry *SeasonEntry) error {
+	query := `
+		INSERT INTO season_entry (season_id, opponent_id, position)
+		VALUES (:season_id, :opponent_id, :position);
+	`
+
+	_, err := r.db.NamedExec(query, entry)
+
+	return err
+}
+
+func (r *repository) GetSeasonEntries(seasonId int) ([]SeasonEntry, error) {
+	query := `SELECT * FROM season_entry WHERE season_id = $1 ORDER BY position ASC`
+
+	entries := []SeasonEntry{}
+
+	if err := r.db.Select(&entries, query, seasonId); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *repository) GetMatchesBySeasonId(seasonId int) ([]Match, error) {
+	query := `SELECT * FROM match WHERE season_id = $1 ORDER BY match_week ASC, id ASC`
+
+	matches := []Match{}
+
+	if err := r.db.Select(&matches, query, seasonId); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// GetOverdueSeasonMatches loads the season fixtures that have not started by
+// their deadline.
+func (r *repository) GetOverdueSeasonMatches(now time.Time) ([]Match, error) {
+	query := `
+		SELECT * FROM match
+		WHERE season_id IS NOT NULL AND status = 'UPCOMING' AND deadline < $1
+		ORDER BY deadline ASC, id ASC
+	`
+
+	matches := []Match{}
+
+	if err := r.db.Select(&matches, query, now); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// ReportFixtureReady records that the opponent is ready to play the season
+// fixture, keeping the first report when it was already made.
+func (r *repository) ReportFixtureReady(readiness *FixtureReadiness) error {
+	query := `
+		INSERT INTO fixture_readiness (match_id, opponent_id)
+		VALUES (:match_id, :opponent_id)
+		ON CONFLICT (match_id, opponent_id) DO NOTHING;
+	`
+
+	_, err := r.db.NamedExec(query, readiness)
+
+	return err
+}
+
+func (r *repository) GetFixtureReadiness(matchId int) ([]FixtureReadiness, error) {
+	query := `SELECT * FROM fixture_readiness WHERE match_id = $1 ORDER BY reported_at ASC`
+
+	readiness := []FixtureReadiness{}
+
+	if err := r.db.Select(&readiness, query, matchId); err != nil {
+		return nil, err
+	}
+
+	return readiness, nil
+}
+
+// LockRating locks the rating of the player or pair, starting it at
+// initialRating when it has not been rated before.
+func (r *repository) LockRating(format string, playerId int, partnerId int, initialRating float64) (*Rating, error) {
+	insert := `
+		INSERT INTO rating (format, player_id, partner_id, rating)
+		VALUES (:format, :player_id, :partner_id, :rating)
+		ON CONFLICT (format, player_id, partner_id) DO NOTHING;
+	`
+	_, err := r.db.NamedExec(insert, &Rating{Format: format, PlayerId: playerId, PartnerId: partnerId, Rating: initialRating})
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT * FROM rating WHERE format = $1 AND player_id = $2 AND partner_id = $3 FOR UPDATE;
+	`
+	var rating Rating
+	if err := r.db.Get(&rating, query, format, playerId, partnerId); err != nil {
+		return nil, err
+	}
+
+	return &rating, nil
+}
+
+func (r *repository) UpdateRating(rating *Rating) error {
+	query := `
+		UPDATE rating
+		SET rating = :rating, matches_rated = :matches_rated
+		WHERE format = :format AND player_id = :player_id AND partner_id = :partner_id;
+	`
+
+	_, err := r.db.NamedExec(query, rating)
+
+	return err
+}
+
+func (r *repository) CreateRatingChange(change *RatingChange) error {
+	query := `
+		INSERT INTO rating_change (match_id, format, player_id, partner_id, rating_before, rating_after)
+		VALUES (:match_id, :format, :player_id, :partner_id, :rating_before, :rating_after);
+	`
+
+	_, err := r.db.NamedExec(query, change)
+
+	return err
+}
+
+func (r *repository) GetRatingChangesByMatchId(matchId int) ([]RatingChange, error) {
+	query := `SELECT * FROM rating_change WHERE match_id = $1 ORDER BY id ASC`
+
+	changes := []RatingChange{}
+
+	if err := r.db.Select(&changes, query, matchId); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *repository) DeleteRatingChangesByMatchId(matchId int) error {
+	query := `
+		DELETE FROM rating_change WHERE match_id = :matchId;
+	`
+
+	_, err := r.db.NamedExec(query, map[string]interface{}{"matchId": matchId})
+
+	return err
+}
+
+func (r *repository) GetRatingChangesByPlayer(format string, playerId int, partnerId int) ([]RatingChange, error) {
+	query := `
+		SELECT * FROM rating_change
+		WHERE format = $1 AND player_id = $2 AND partner_id = $3
+		ORDER BY created_at ASC, id ASC
+	`
+
+	changes := []RatingChange{}
+
+	if err := r.db.Select(&changes, query, format, playerId, partnerId); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+type RankingRow struct {
+	PlayerId     int     `db:"player_id"`
+	PartnerId    int     `db:"partner_id"`
+	Name         string  `db:"name"`
+	Rating       float64 `db:"rating"`
+	MatchesRated int     `db:"matches_rated"`
+}
+
+// GetRankings lists the rated players for SINGLES or pairs for DOUBLES from
+// the highest rating down.
+func (r *repository) GetRankings(format string) ([]RankingRow, error) {
+	query := `
+		SELECT rating.player_id, rating.partner_id, rating.rating, rating.matches_rated,
+			CASE WHEN rating.format = 'DOUBLES'
+				THEN COALESCE(player.name || ' & ' || partner.name, '')
+				ELSE COALESCE(player.name, '')
+			END AS name
+		FROM rating
+		LEFT JOIN player ON player.id = rating.player_id
+		LEFT JOIN player AS partner ON rating.format = 'DOUBLES' AND partner.id = rating.partner_id
+		WHERE rating.format = $1 AND rating.matches_rated > 0
+		ORDER BY rating.rating DESC, rating.player_id ASC, rating.partner_id ASC
+	`
+
+	rows := []RankingRow{}
+
+	if err := r.db.Select(&rows, query, format); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,10 @@
+package enums
+
+type BracketType string
+
+const (
+	MainBracket        BracketType = "MAIN"
+	ConsolationBracket BracketType = "CONSOLATION"
+	LosersBracket      BracketType = "LOSERS"
+	GrandFinalBracket  BracketType = "GRAND_FINAL"
+)
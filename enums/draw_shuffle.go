@@ -0,0 +1,8 @@
+package enums
+
+type DrawShuffle string
+
+const (
+	GoMathRandShuffle        DrawShuffle = "GO_MATH_RAND"
+	Sha256FisherYatesShuffle DrawShuffle = "SHA256_FISHER_YATES"
+)
@@ -0,0 +1,9 @@
+package enums
+
+type DrawType string
+
+const (
+	SingleElimination DrawType = "SINGLE_ELIMINATION"
+	ConsolationDraw   DrawType = "CONSOLATION"
+	DoubleElimination DrawType = "DOUBLE_ELIMINATION"
+)
@@ -0,0 +1,9 @@
+package enums
+
+type Gender string
+
+const (
+	Male   Gender = "MALE"
+	Female Gender = "FEMALE"
+	Other  Gender = "OTHER"
+)
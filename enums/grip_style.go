@@ -0,0 +1,8 @@
+package enums
+
+type GripStyle string
+
+const (
+	Shakehand GripStyle = "SHAKEHAND"
+	Penhold   GripStyle = "PENHOLD"
+)
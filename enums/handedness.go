@@ -0,0 +1,8 @@
+package enums
+
+type Handedness string
+
+const (
+	RightHanded Handedness = "RIGHT"
+	LeftHanded  Handedness = "LEFT"
+)
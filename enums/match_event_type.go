@@ -0,0 +1,14 @@
+package enums
+
+type MatchEventType string
+
+const (
+	Timeout         MatchEventType = "TIMEOUT"
+	YellowCard      MatchEventType = "YELLOW_CARD"
+	YellowRedCard   MatchEventType = "YELLOW_RED_CARD"
+	RedCard         MatchEventType = "RED_CARD"
+	Let             MatchEventType = "LET"
+	MedicalBreak    MatchEventType = "MEDICAL_BREAK"
+	ChangeEnds      MatchEventType = "CHANGE_ENDS"
+	ScoreCorrection MatchEventType = "SCORE_CORRECTION"
+)
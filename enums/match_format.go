@@ -5,4 +5,5 @@ type MatchFormat string
 const (
 	Singles MatchFormat = "SINGLES"
 	Doubles MatchFormat = "DOUBLES"
+	Team    MatchFormat = "TEAM"
 )
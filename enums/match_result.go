@@ -0,0 +1,11 @@
+package enums
+
+type MatchResult string
+
+const (
+	Completed    MatchResult = "COMPLETED"
+	Walkover     MatchResult = "WALKOVER"
+	Retired      MatchResult = "RETIRED"
+	Disqualified MatchResult = "DISQUALIFIED"
+	Unplayed     MatchResult = "UNPLAYED"
+)
@@ -8,4 +8,5 @@ const (
 	QuarterFinal MatchStage = "QUARTER_FINAL"
 	SemiFinal    MatchStage = "SEMI_FINAL"
 	Final        MatchStage = "FINAL"
+	League       MatchStage = "LEAGUE"
 )
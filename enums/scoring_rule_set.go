@@ -0,0 +1,9 @@
+package enums
+
+type ScoringRuleSet string
+
+const (
+	StandardRules    ScoringRuleSet = "STANDARD"
+	GoldenPointRules ScoringRuleSet = "GOLDEN_POINT"
+	CappedRules      ScoringRuleSet = "CAPPED"
+)
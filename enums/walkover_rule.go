@@ -0,0 +1,8 @@
+package enums
+
+type WalkoverRule string
+
+const (
+	HomeWins         WalkoverRule = "HOME"
+	HigherPlacedWins WalkoverRule = "HIGHER_PLACED"
+)
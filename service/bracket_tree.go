@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrBracketNotFound = errors.New("bracket not generated for the event")
+
+// BracketNode is a bracket match together with the matches feeding its slots.
+type BracketNode struct {
+	MatchId  int
+	Round    int
+	Position int
+	Stage    string
+	Status   string
+	Result   string
+	Slots    [2]BracketSlot
+}
+
+// BracketSlot is opponent A or B of a bracket match. Feeder is the match whose
+// winner or loser moves into the slot, and is nil for a slot filled straight
+// from the draw.
+type BracketSlot struct {
+	OpponentId int
+	Name       string
+	Score      int
+	IsWinner   bool
+	Feeder     *BracketNode
+}
+
+// GetBracketTree returns the given bracket of the event as a tree rooted at
+// its last match. Slots show the opponents and sets won so far.
+func (s *service) GetBracketTree(eventId int, bracket enums.BracketType) (*BracketNode, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+	matches := []db.Match{}
+	if err := s.repo.GetAllMatches(&matches, "", nil, &eventId); err != nil {
+		return nil, err
+	}
+
+	inBracket := make(map[int]db.Match)
+	for _, match := range matches {
+		if match.Bracket == string(bracket) {
+			inBracket[match.Id] = match
+		}
+	}
+
+	feeders := make(map[bracketSlot]db.Match)
+	var root *db.Match
+	for _, match := range inBracket {
+		match := match
+		if match.NextMatchId != nil && match.NextMatchSlotIsA != nil {
+			if _, ok := inBracket[*match.NextMatchId]; ok {
+				feeders[bracketSlot{MatchId: *match.NextMatchId, IsA: *match.NextMatchSlotIsA}] = match
+				continue
+			}
+		}
+		if root == nil || bracketRoundOf(match) > bracketRoundOf(*root) {
+			root = &match
+		}
+	}
+	if root == nil {
+		return nil, ErrBracketNotFound
+	}
+
+	return s.bracketNode(*root, feeders)
+}
+
+func (s *service) bracketNode(match db.Match, feeders map[bracketSlot]db.Match) (*BracketNode, error) {
+	opponents, err := s.opponentsFromMatch(match)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := s.repo.GetSetsByMatchId(match.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &BracketNode{
+		MatchId: match.Id,
+		Round:   bracketRoundOf(match),
+		Stage:   match.Stage,
+		Status:  match.Status,
+		Result:  match.Result,
+	}
+	if match.BracketPosition != nil {
+		node.Position = *match.BracketPosition
+	}
+	for i, opp := range opponents {
+		node.Slots[i] = BracketSlot{OpponentId: opp.Id, Name: opp.Name, IsWinner: opp.IsWinner}
+	}
+	for _, set := range sets {
+		if !set.IsCompleted {
+			continue
+		}
+		if set.OpponentAScore > set.OpponentBScore {
+			node.Slots[0].Score += 1
+		} else {
+			node.Slots[1].Score += 1
+		}
+	}
+
+	for i := range node.Slots {
+		feeder, ok := feeders[bracketSlot{MatchId: match.Id, IsA: i == 0}]
+		if !ok {
+			continue
+		}
+		child, err := s.bracketNode(feeder, feeders)
+		if err != nil {
+			return nil, err
+		}
+		node.Slots[i].Feeder = child
+	}
+	return node, nil
+}
+
+func bracketRoundOf(match db.Match) int {
+	if match.BracketRound == nil {
+		return 0
+	}
+	return *match.BracketRound
+}
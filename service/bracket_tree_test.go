@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestGetBracketTree(t *testing.T) {
+	repo, svc := newBracketFixture()
+	repo.registered = []db.Player{{Id: 1, Name: "Ana"}, {Id: 2, Name: "Bo"}, {Id: 3, Name: "Cai"}}
+	if err := svc.createBracket(1, placeBySeed(seededEntries(3)), enums.SingleElimination, bracketSettings()); err != nil {
+		t.Fatal(err)
+	}
+	var semiFinal db.Match
+	for _, match := range repo.matches {
+		if *match.BracketRound == 1 {
+			semiFinal = match
+		}
+	}
+	if _, err := svc.EndMatch(semiFinal.Id, enums.Walkover, false); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := svc.GetBracketTree(1, enums.MainBracket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root.Round != 2 || root.Stage != string(enums.Final) {
+		t.Errorf("root is round %d %s, want the final in round 2", root.Round, root.Stage)
+	}
+	// Seed 1 has a bye into the final and seed 3 beat seed 2 in the other
+	// half.
+	if slot := root.Slots[0]; slot.Name != "Ana" || slot.Feeder != nil {
+		t.Errorf("final slot A = %+v, want Ana from the draw", slot)
+	}
+	slot := root.Slots[1]
+	if slot.Name != "Cai" || slot.Feeder == nil {
+		t.Fatalf("final slot B = %+v, want Cai fed by the semi final", slot)
+	}
+	feeder := slot.Feeder
+	if feeder.MatchId != semiFinal.Id || feeder.Result != string(enums.Walkover) {
+		t.Errorf("feeder = match %d %s, want the semi final walkover", feeder.MatchId, feeder.Result)
+	}
+	if feeder.Slots[0].Name != "Bo" || feeder.Slots[0].IsWinner || !feeder.Slots[1].IsWinner {
+		t.Errorf("semi final slots = %+v, want Cai beating Bo", feeder.Slots)
+	}
+}
+
+func TestGetBracketTreeWithoutBracket(t *testing.T) {
+	_, svc := newBracketFixture()
+	if err := svc.createBracket(1, placeBySeed(seededEntries(4)), enums.SingleElimination, bracketSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.GetBracketTree(1, enums.LosersBracket); !errors.Is(err, ErrBracketNotFound) {
+		t.Errorf("GetBracketTree() error = %v, want %v", err, ErrBracketNotFound)
+	}
+	if _, err := svc.GetBracketTree(2, enums.MainBracket); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("GetBracketTree() error = %v, want %v", err, ErrEventNotFound)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+// DecidingSetChangeEndsScore is the score at which the players change ends in
+// the deciding set of a match.
+const DecidingSetChangeEndsScore = 5
+
+// handleChangeOfEnds records a change of ends after a set that did not decide
+// the match, and when an opponent first reaches DecidingSetChangeEndsScore in
+// the deciding set. With autoNextSet the next set is opened once the set is
+// over.
+func (s *service) handleChangeOfEnds(match *db.Match, set *db.Set, autoNextSet bool) error {
+	if !set.IsCompleted {
+		if set.SetNumber == match.SetCount && reachedDecidingSetChangeEnds(*match, *set) {
+			return s.recordChangeOfEnds(match.Id, set.Id)
+		}
+		return nil
+	}
+
+	match, err := s.repo.GetMatchById(match.Id)
+	if err != nil {
+		return err
+	}
+	if match.Status == string(enums.Past) {
+		return nil
+	}
+
+	err = s.recordChangeOfEnds(match.Id, set.Id)
+	if err != nil {
+		return err
+	}
+
+	if autoNextSet {
+		return s.createSet(match.Id, nil)
+	}
+	return nil
+}
+
+// recordChangeOfEnds adds a change of ends event to the set unless one was
+// already recorded, e.g. before a point was undone and scored again.
+func (s *service) recordChangeOfEnds(matchId int, setId int) error {
+	events, err := s.repo.GetMatchEventsByMatchId(matchId)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Type == string(enums.ChangeEnds) && event.SetId != nil && *event.SetId == setId {
+			return nil
+		}
+	}
+
+	return s.repo.CreateMatchEvent(&db.MatchEvent{
+		MatchId: matchId,
+		SetId:   &setId,
+		Type:    string(enums.ChangeEnds),
+	})
+}
+
+// reachedDecidingSetChangeEnds reports whether the last point took an
+// opponent to DecidingSetChangeEndsScore for the first time in the set,
+// counting only the points scored on top of the handicap.
+func reachedDecidingSetChangeEnds(match db.Match, set db.Set) bool {
+	scoredByA, scoredByB := pointsScored(match, set.OpponentAScore, set.OpponentBScore)
+	if scoredByA == DecidingSetChangeEndsScore && scoredByB < DecidingSetChangeEndsScore {
+		return true
+	}
+	return scoredByB == DecidingSetChangeEndsScore && scoredByA < DecidingSetChangeEndsScore
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestReachedDecidingSetChangeEnds(t *testing.T) {
+	tests := []struct {
+		name                 string
+		handicapA, handicapB int
+		a, b                 int
+		want                 bool
+	}{
+		{"first to five", 0, 0, 5, 3, true},
+		{"both past five", 0, 0, 6, 5, false},
+		{"handicap only", 5, 0, 5, 0, false},
+		{"handicap and one point", 5, 0, 6, 0, false},
+		{"five on top of the handicap", 5, 0, 10, 2, true},
+		{"five on top of the opponent's handicap", 0, 6, 4, 11, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := db.Match{HandicapA: tt.handicapA, HandicapB: tt.handicapB}
+			set := db.Set{OpponentAScore: tt.a, OpponentBScore: tt.b}
+			if got := reachedDecidingSetChangeEnds(match, set); got != tt.want {
+				t.Errorf("reachedDecidingSetChangeEnds(%d-%d) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndoKeepsDecidingSetChangeEndsWithHandicap(t *testing.T) {
+	repo := newFakeRepository(db.Match{
+		Id:             1,
+		Format:         string(enums.Singles),
+		GamePoint:      11,
+		SetCount:       1,
+		Status:         string(enums.Ongoing),
+		ScoringRules:   string(enums.StandardRules),
+		HandicapA:      5,
+		FirstServerIsA: true,
+	})
+	setId, _ := repo.CreateSet(&db.Set{
+		SetNumber:      1,
+		MatchId:        1,
+		OpponentAScore: 5,
+		StartedAt:      time.Now(),
+	})
+	svc := &service{repo: repo}
+
+	changesOfEnds := func() int {
+		count := 0
+		for _, e := range repo.events {
+			if e.Type == string(enums.ChangeEnds) {
+				count += 1
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := svc.UpdateScore(1, int(setId), true, false, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := changesOfEnds(); got != 0 {
+		t.Fatalf("changes of ends at 9-0 = %d, want 0", got)
+	}
+
+	if _, err := svc.UpdateScore(1, int(setId), true, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := changesOfEnds(); got != 1 {
+		t.Fatalf("changes of ends at 10-0 = %d, want 1", got)
+	}
+
+	if _, err := svc.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := changesOfEnds(); got != 0 {
+		t.Fatalf("changes of ends after undo to 9-0 = %d, want 0", got)
+	}
+}
+
+func TestAutoNextSet(t *testing.T) {
+	tests := []struct {
+		name        string
+		setCount    int
+		autoNextSet bool
+		wantSets    int
+	}{
+		{"opens the next set", 3, true, 2},
+		{"waits for the umpire", 3, false, 1},
+		{"stops once the match is won", 1, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, svc := newScoringFixture(t, tt.setCount)
+			setId := repo.sets[0].Id
+			for i := 0; i < 11; i++ {
+				if _, err := svc.UpdateScore(1, setId, true, tt.autoNextSet, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(repo.sets) != tt.wantSets {
+				t.Errorf("%d sets, want %d", len(repo.sets), tt.wantSets)
+			}
+			changeEnds := 0
+			for _, e := range repo.events {
+				if e.Type == string(enums.ChangeEnds) && e.SetId != nil && *e.SetId == setId {
+					changeEnds += 1
+				}
+			}
+			if changeEnds != 1 {
+				t.Errorf("%d change of ends in the first set, want 1", changeEnds)
+			}
+		})
+	}
+}
+
+func TestDecidingSetChangeEndsRecordedOnce(t *testing.T) {
+	repo, svc := newScoringFixture(t, 1)
+	setId := repo.sets[0].Id
+	for _, scoredByA := range []bool{true, true, true, true, true, false, true} {
+		if _, err := svc.UpdateScore(1, setId, scoredByA, false, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(repo.events) != 1 || repo.events[0].Type != string(enums.ChangeEnds) {
+		t.Errorf("events = %+v, want one change of ends", repo.events)
+	}
+}
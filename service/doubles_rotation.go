@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrNotDoublesMatch = errors.New("not a doubles match")
+var ErrSetAlreadyStarted = errors.New("set already started")
+var ErrFirstReceiverRequired = errors.New("first receiver required")
+
+// SetDoublesServiceOrder records which member of each pair serves and
+// receives first in a doubles set. In every set after the first the first
+// receiver is the player who served to the first server in the previous set,
+// so firstReceiverIsPlayerA is only needed when that cannot be worked out.
+func (s *service) SetDoublesServiceOrder(
+	matchId int,
+	setId int,
+	firstServerIsPlayerA bool,
+	firstReceiverIsPlayerA *bool,
+) error {
+	return s.inTx(func(tx *service) error {
+		match, set, err := tx.getOpenSet(matchId, setId)
+		if err != nil {
+			return err
+		}
+		if match.Format != string(enums.Doubles) {
+			return ErrNotDoublesMatch
+		}
+		limit := 1
+		setLogs, err := tx.repo.GetSetLogsBySetId(set.Id, &limit)
+		if err != nil {
+			return err
+		}
+		if len(setLogs) > 0 {
+			return ErrSetAlreadyStarted
+		}
+
+		if set.SetNumber > 1 {
+			sets, err := tx.repo.GetSetsByMatchId(matchId)
+			if err != nil {
+				return err
+			}
+			previous := sets[set.SetNumber-2]
+			if previous.FirstServerIsPlayerA != nil && previous.FirstReceiverIsPlayerA != nil {
+				receiver := nextSetFirstReceiver(previous, firstServerIsPlayerA)
+				firstReceiverIsPlayerA = &receiver
+			}
+		}
+		if firstReceiverIsPlayerA == nil {
+			return ErrFirstReceiverRequired
+		}
+
+		set.FirstServerIsPlayerA = &firstServerIsPlayerA
+		set.FirstReceiverIsPlayerA = firstReceiverIsPlayerA
+
+		return tx.repo.UpdateSet(set)
+	})
+}
+
+// nextSetFirstReceiver returns the player who served to firstServerIsPlayerA
+// in the previous set. The first server of the next set always comes from the
+// pair that received first in the previous set.
+func nextSetFirstReceiver(previous db.Set, firstServerIsPlayerA bool) bool {
+	if firstServerIsPlayerA == *previous.FirstReceiverIsPlayerA {
+		return *previous.FirstServerIsPlayerA
+	}
+	return !*previous.FirstServerIsPlayerA
+}
+
+// doublesServiceOrder returns which member of the serving pair serves the
+// next point and which member of the receiving pair receives it. On every
+// change of service the receiver becomes the server and the partner of the
+// previous server becomes the receiver. When the players change ends in the
+// deciding set the pair due next to receive changes its receiving order.
+// logs must be ordered oldest first.
+func doublesServiceOrder(match db.Match, set db.Set, logs []db.SetLog) (bool, bool, bool) {
+	if set.FirstServerIsPlayerA == nil || set.FirstReceiverIsPlayerA == nil {
+		return false, false, false
+	}
+
+	firstServerIsA := setFirstServerIsA(match, set.SetNumber)
+	swapAt := -1
+	if set.SetNumber == match.SetCount {
+		for i, log := range logs {
+			scoredByA, scoredByB := pointsScored(match, log.OppAScore, log.OppBScore)
+			if scoredByA >= DecidingSetChangeEndsScore || scoredByB >= DecidingSetChangeEndsScore {
+				swapAt = i + 1
+				break
+			}
+		}
+	}
+
+	server := *set.FirstServerIsPlayerA
+	receiver := *set.FirstReceiverIsPlayerA
+	servingTeamIsA := firstServerIsA
+	for played := 1; played <= len(logs); played++ {
+		nextServingTeamIsA := serverIsA(firstServerIsA, played, deuceFromPoint(match), set.ExpediteFromPoint)
+		if nextServingTeamIsA != servingTeamIsA {
+			server, receiver = receiver, !server
+			servingTeamIsA = nextServingTeamIsA
+		}
+		if played == swapAt {
+			receiver = !receiver
+		}
+	}
+
+	return server, receiver, true
+}
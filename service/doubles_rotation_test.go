@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+)
+
+func TestDoublesServiceOrderWithHandicap(t *testing.T) {
+	match := db.Match{GamePoint: 11, SetCount: 1, HandicapA: 5, FirstServerIsA: true}
+	firstServer, firstReceiver := true, true
+	set := db.Set{
+		SetNumber:              1,
+		OpponentAScore:         5,
+		FirstServerIsPlayerA:   &firstServer,
+		FirstReceiverIsPlayerA: &firstReceiver,
+	}
+
+	logsOf := func(scores ...[2]int) []db.SetLog {
+		logs := make([]db.SetLog, 0, len(scores))
+		for _, s := range scores {
+			logs = append(logs, db.SetLog{OppAScore: s[0], OppBScore: s[1]})
+		}
+		return logs
+	}
+
+	tests := []struct {
+		name         string
+		logs         []db.SetLog
+		wantServer   bool
+		wantReceiver bool
+	}{
+		{"first point keeps the receiving order", logsOf([2]int{5, 1}), true, true},
+		{"five points on top of the handicap swap the receiving order",
+			logsOf([2]int{6, 0}, [2]int{7, 0}, [2]int{8, 0}, [2]int{9, 0}, [2]int{10, 0}), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, receiver, ok := doublesServiceOrder(match, set, tt.logs)
+			if !ok {
+				t.Fatal("doublesServiceOrder() not ok")
+			}
+			if server != tt.wantServer || receiver != tt.wantReceiver {
+				t.Errorf("doublesServiceOrder() = %v, %v, want %v, %v", server, receiver, tt.wantServer, tt.wantReceiver)
+			}
+		})
+	}
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrDrawNotFound = errors.New("no draw held for the event")
+var ErrDrawCommitmentNotFound = errors.New("no random seed committed for the event")
+var ErrDrawSeedAlreadyCommitted = errors.New("random seed already committed for the event")
+var ErrInvalidSeedHash = errors.New("seed hash must be a hex encoded SHA-256 hash")
+var ErrDrawSeedNotCommitted = errors.New("a chosen random seed must be committed before the draw")
+var ErrDrawSeedMismatch = errors.New("random seed does not match the committed hash")
+var ErrDrawSeedNotRevealed = errors.New("the committed random seed must be given to hold the draw")
+var ErrDrawSeedAfterEntries = errors.New("a random seed can only be committed before entries are added to the event")
+var ErrDrawEntriesNotRegistered = errors.New("a draw with a committed random seed must be held with the entries of the event")
+
+type drawRecord struct {
+	Id         int
+	EventId    int
+	RandomSeed int64
+	DrawType   enums.DrawType
+	Shuffle    enums.DrawShuffle
+	CreatedAt  time.Time
+	Entries    []drawRecordEntry
+	Commitment *drawCommitment
+}
+
+// drawCommitment is the hash of a random seed chosen for the draw of an
+// event, published before the draw and recording who chose the seed.
+type drawCommitment struct {
+	EventId     int
+	SeedHash    string
+	CommittedBy string
+	CreatedAt   time.Time
+}
+
+// drawRecordEntry is an entry of a draw in the order it was given, with the
+// slot of the first round it was drawn into.
+type drawRecordEntry struct {
+	OpponentId int
+	Seed       int
+	Slot       int
+}
+
+type drawVerification struct {
+	EventId    int
+	RandomSeed int64
+	Mismatches []string
+}
+
+// CommitDrawSeed fixes the random seed of the draw of the event ahead of the
+// draw by storing its hash, the hex encoded SHA-256 hash of the seed written
+// in decimal. The commitment can only be made once and only while the event
+// has no entries, and the draw is then held with the entries added to the
+// event afterwards, so the seed cannot be chosen to suit the entries.
+func (s *service) CommitDrawSeed(eventId int, seedHash string, committedBy string) (*drawCommitment, error) {
+	var commitment *drawCommitment
+	err := s.inTx(func(tx *service) error {
+		c, err := tx.commitDrawSeed(eventId, seedHash, committedBy)
+		commitment = c
+		return err
+	})
+	return commitment, err
+}
+
+func (s *service) commitDrawSeed(eventId int, seedHash string, committedBy string) (*drawCommitment, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+	seedHash = strings.ToLower(seedHash)
+	if hash, err := hex.DecodeString(seedHash); err != nil || len(hash) != sha256.Size {
+		return nil, ErrInvalidSeedHash
+	}
+	_, err := s.repo.GetDrawByEventId(eventId)
+	if err == nil {
+		return nil, ErrBracketAlreadyGenerated
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	entries, err := s.repo.GetEventEntries(eventId)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return nil, ErrDrawSeedAfterEntries
+	}
+
+	err = s.repo.CreateDrawCommitment(&db.DrawCommitment{EventId: eventId, SeedHash: seedHash, CommittedBy: committedBy})
+	if errors.Is(err, db.ErrUniqueViolation) {
+		return nil, ErrDrawSeedAlreadyCommitted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getDrawCommitment(eventId)
+}
+
+func (s *service) GetDrawCommitment(eventId int) (*drawCommitment, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+	return s.getDrawCommitment(eventId)
+}
+
+func (s *service) getDrawCommitment(eventId int) (*drawCommitment, error) {
+	c, err := s.repo.GetDrawCommitment(eventId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDrawCommitmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &drawCommitment{
+		EventId:     c.EventId,
+		SeedHash:    c.SeedHash,
+		CommittedBy: c.CommittedBy,
+		CreatedAt:   c.CreatedAt,
+	}, nil
+}
+
+// sameEntries reports whether the entries are the entries registered for the
+// event, with the same seeds, in any order.
+func sameEntries(entries []BracketEntry, registered []db.EventEntry) bool {
+	if len(entries) != len(registered) {
+		return false
+	}
+	seeds := make(map[int]int)
+	for _, entry := range registered {
+		seeds[entry.OpponentId] = entry.Seed
+	}
+	for _, entry := range entries {
+		seed := entry.Seed
+		if seed < 0 {
+			seed = 0
+		}
+		registeredSeed, ok := seeds[entry.OpponentId]
+		if !ok || registeredSeed != seed {
+			return false
+		}
+		delete(seeds, entry.OpponentId)
+	}
+	return true
+}
+
+// drawSeedHash is the hash a random seed is committed with.
+func drawSeedHash(seed int64) string {
+	hash := sha256.Sum256([]byte(strconv.FormatInt(seed, 10)))
+	return hex.EncodeToString(hash[:])
+}
+
+// HoldDraw places the entries into a knockout bracket of the event like
+// GenerateKnockoutBracket, except that the unseeded entries are shuffled with
+// the random seed before they are placed, as described by
+// shuffleSha256FisherYates. A chosen seed must match the seed committed with
+// CommitDrawSeed, and the entries must then be those of the event, while
+// without a commitment a seed is picked at random. Anyone holding the seed
+// and the entries can repeat the draw. The seed, the entries and their slots
+// are stored.
+func (s *service) HoldDraw(
+	eventId int,
+	entries []BracketEntry,
+	randomSeed *int64,
+	drawType enums.DrawType,
+	settings MatchSettings,
+) (*drawRecord, error) {
+	var record *drawRecord
+	err := s.inTx(func(tx *service) error {
+		r, err := tx.holdDraw(eventId, entries, randomSeed, drawType, settings)
+		record = r
+		return err
+	})
+	return record, err
+}
+
+func (s *service) holdDraw(
+	eventId int,
+	entries []BracketEntry,
+	randomSeed *int64,
+	drawType enums.DrawType,
+	settings MatchSettings,
+) (*drawRecord, error) {
+	commitment, err := s.getDrawCommitment(eventId)
+	if errors.Is(err, ErrDrawCommitmentNotFound) {
+		commitment = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	seed := int64(0)
+	if randomSeed != nil {
+		if commitment == nil {
+			return nil, ErrDrawSeedNotCommitted
+		}
+		if drawSeedHash(*randomSeed) != commitment.SeedHash {
+			return nil, ErrDrawSeedMismatch
+		}
+		registered, err := s.repo.GetEventEntries(eventId)
+		if err != nil {
+			return nil, err
+		}
+		if !sameEntries(entries, registered) {
+			return nil, ErrDrawEntriesNotRegistered
+		}
+		seed = *randomSeed
+	} else if commitment != nil {
+		return nil, ErrDrawSeedNotRevealed
+	} else {
+		// Picked seeds stay within the integers a JSON client reads exactly.
+		n, err := crand.Int(crand.Reader, big.NewInt(1<<53))
+		if err != nil {
+			return nil, err
+		}
+		seed = n.Int64()
+	}
+
+	shuffle := enums.Sha256FisherYatesShuffle
+	placement, err := seededPlacement(entries, seed, shuffle)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.createBracket(eventId, placement, drawType, settings); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.CreateDraw(&db.Draw{
+		EventId:    eventId,
+		RandomSeed: seed,
+		DrawType:   string(drawType),
+		Shuffle:    string(shuffle),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[int]int)
+	for slot, opponentId := range placement {
+		if opponentId != 0 {
+			slots[opponentId] = slot
+		}
+	}
+	for i, entry := range entries {
+		err := s.repo.AddDrawEntry(&db.DrawEntry{
+			DrawId:     int(id),
+			OpponentId: entry.OpponentId,
+			Seed:       entry.Seed,
+			EntryOrder: i + 1,
+			Slot:       slots[entry.OpponentId],
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getDraw(eventId)
+}
+
+func (s *service) GetDraw(eventId int) (*drawRecord, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+	return s.getDraw(eventId)
+}
+
+func (s *service) getDraw(eventId int) (*drawRecord, error) {
+	d, err := s.repo.GetDrawByEventId(eventId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDrawNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetDrawEntries(d.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, err := s.getDrawCommitment(eventId)
+	if errors.Is(err, ErrDrawCommitmentNotFound) {
+		commitment = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	record := &drawRecord{
+		Id:         d.Id,
+		EventId:    d.EventId,
+		RandomSeed: d.RandomSeed,
+		DrawType:   enums.DrawType(d.DrawType),
+		Shuffle:    enums.DrawShuffle(d.Shuffle),
+		CreatedAt:  d.CreatedAt,
+		Entries:    make([]drawRecordEntry, 0, len(entries)),
+		Commitment: commitment,
+	}
+	for _, entry := range entries {
+		record.Entries = append(record.Entries, drawRecordEntry{
+			OpponentId: entry.OpponentId,
+			Seed:       entry.Seed,
+			Slot:       entry.Slot,
+		})
+	}
+	return record, nil
+}
+
+// VerifyDraw repeats the stored draw of the event from its seed and entries,
+// and reports every slot where the stored placement or the first round of the
+// bracket differs from the repeated draw.
+func (s *service) VerifyDraw(eventId int) (*drawVerification, error) {
+	record, err := s.GetDraw(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]BracketEntry, 0, len(record.Entries))
+	for _, entry := range record.Entries {
+		entries = append(entries, BracketEntry{OpponentId: entry.OpponentId, Seed: entry.Seed})
+	}
+	placement, err := seededPlacement(entries, record.RandomSeed, record.Shuffle)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &drawVerification{EventId: eventId, RandomSeed: record.RandomSeed, Mismatches: make([]string, 0)}
+	for _, entry := range record.Entries {
+		if placement[entry.Slot] != entry.OpponentId {
+			verification.Mismatches = append(verification.Mismatches,
+				fmt.Sprintf("opponent %d stored in slot %d but drawn into another slot", entry.OpponentId, entry.Slot))
+		}
+	}
+
+	mismatches, err := s.checkFirstRound(eventId, placement)
+	if err != nil {
+		return nil, err
+	}
+	verification.Mismatches = append(verification.Mismatches, mismatches...)
+	return verification, nil
+}
+
+// checkFirstRound compares the first round of the main bracket of the event
+// with the placement. An opponent with a bye is looked for in the second
+// round.
+func (s *service) checkFirstRound(eventId int, placement []int) ([]string, error) {
+	matches := []db.Match{}
+	if err := s.repo.GetAllMatches(&matches, "", nil, &eventId); err != nil {
+		return nil, err
+	}
+
+	byRound := make(map[bracketFeed]db.Match)
+	for _, match := range matches {
+		if match.Bracket != string(enums.MainBracket) || match.BracketRound == nil || match.BracketPosition == nil {
+			continue
+		}
+		byRound[bracketFeed{Round: *match.BracketRound, Position: *match.BracketPosition}] = match
+	}
+
+	mismatches := make([]string, 0)
+	for position := 0; position < len(placement)/2; position++ {
+		drawnA, drawnB := placement[2*position], placement[2*position+1]
+
+		if match, ok := byRound[bracketFeed{Round: 1, Position: position}]; ok {
+			opponentIds, err := s.opponentIdsFromMatch(match)
+			if err != nil {
+				return nil, err
+			}
+			if opponentIds[true] != drawnA || opponentIds[false] != drawnB {
+				mismatches = append(mismatches, fmt.Sprintf(
+					"match %d is %d against %d but %d against %d were drawn",
+					match.Id, opponentIds[true], opponentIds[false], drawnA, drawnB,
+				))
+			}
+			continue
+		}
+
+		if drawnA != 0 && drawnB != 0 {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"no first round match for %d against %d", drawnA, drawnB,
+			))
+			continue
+		}
+		next, ok := byRound[bracketFeed{Round: 2, Position: position / 2}]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("no second round match for the bye of %d", drawnA+drawnB))
+			continue
+		}
+		opponentIds, err := s.opponentIdsFromMatch(next)
+		if err != nil {
+			return nil, err
+		}
+		if opponentIds[position%2 == 0] != drawnA+drawnB {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"match %d holds %d after a bye but %d was drawn a bye",
+				next.Id, opponentIds[position%2 == 0], drawnA+drawnB,
+			))
+		}
+	}
+	return mismatches, nil
+}
+
+// seededPlacement orders the entries by seed, shuffles the unseeded entries
+// with the random seed and places them like a seeded bracket. Draws held with
+// GoMathRandShuffle shuffled the unseeded entries in the order they were
+// given with math/rand, and are only repeated to verify them.
+func seededPlacement(entries []BracketEntry, randomSeed int64, shuffle enums.DrawShuffle) ([]int, error) {
+	ordered, err := orderBySeed(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	seededCount := 0
+	for seededCount < len(ordered) && ordered[seededCount].Seed > 0 {
+		seededCount++
+	}
+	unseeded := ordered[seededCount:]
+	swap := func(i, j int) {
+		unseeded[i], unseeded[j] = unseeded[j], unseeded[i]
+	}
+	if shuffle == enums.GoMathRandShuffle {
+		rand.New(rand.NewSource(randomSeed)).Shuffle(len(unseeded), swap)
+	} else {
+		sort.Slice(unseeded, func(i, j int) bool {
+			return unseeded[i].OpponentId < unseeded[j].OpponentId
+		})
+		shuffleSha256FisherYates(len(unseeded), randomSeed, swap)
+	}
+
+	return placeBySeed(ordered), nil
+}
+
+// shuffleSha256FisherYates shuffles n items, the unseeded entries ordered by
+// opponent id, with a Fisher–Yates shuffle driven by the random seed:
+//
+//   - Block k of the random stream, counting from 0, is the SHA-256 hash of
+//     the seed followed by k, both as 8 byte big-endian integers. The stream
+//     is read as consecutive 8 byte big-endian unsigned integers.
+//   - For i from n-1 down to 1, j is drawn uniformly from 0 to i by reading
+//     integers x until x < 2^64 - (2^64 mod (i+1)) and taking x mod (i+1).
+//     Items i and j are then swapped.
+func shuffleSha256FisherYates(n int, randomSeed int64, swap func(i, j int)) {
+	stream := &seedStream{seed: randomSeed}
+	for i := n - 1; i > 0; i-- {
+		j := stream.intn(uint64(i + 1))
+		swap(i, int(j))
+	}
+}
+
+// seedStream is the random stream of shuffleSha256FisherYates.
+type seedStream struct {
+	seed    int64
+	counter uint64
+	block   []byte
+}
+
+func (r *seedStream) uint64() uint64 {
+	if len(r.block) == 0 {
+		input := make([]byte, 16)
+		binary.BigEndian.PutUint64(input[:8], uint64(r.seed))
+		binary.BigEndian.PutUint64(input[8:], r.counter)
+		hash := sha256.Sum256(input)
+		r.block = hash[:]
+		r.counter += 1
+	}
+	x := binary.BigEndian.Uint64(r.block[:8])
+	r.block = r.block[8:]
+	return x
+}
+
+// intn draws uniformly from 0 to n-1, rejecting the integers at the top of
+// the range that would favour the lower results.
+func (r *seedStream) intn(n uint64) uint64 {
+	rem := (math.MaxUint64%n + 1) % n
+	for {
+		x := r.uint64()
+		if rem == 0 || x <= math.MaxUint64-rem {
+			return x % n
+		}
+	}
+}
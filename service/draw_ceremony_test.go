@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestShuffleSha256FisherYates(t *testing.T) {
+	tests := []struct {
+		seed int64
+		want []int
+	}{
+		{42, []int{6, 1, 2, 9, 0, 7, 5, 4, 8, 3}},
+		{1, []int{3, 5, 0, 8, 6, 7, 4, 9, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		items := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		shuffleSha256FisherYates(len(items), tt.seed, func(i, j int) {
+			items[i], items[j] = items[j], items[i]
+		})
+		if !reflect.DeepEqual(items, tt.want) {
+			t.Errorf("shuffleSha256FisherYates(seed %d) = %v, want %v", tt.seed, items, tt.want)
+		}
+	}
+}
+
+func TestSeededPlacementIgnoresEntryOrder(t *testing.T) {
+	entries := []BracketEntry{{OpponentId: 1, Seed: 1}, {OpponentId: 2, Seed: 2}}
+	for id := 3; id <= 8; id++ {
+		entries = append(entries, BracketEntry{OpponentId: id})
+	}
+	reversed := append([]BracketEntry{}, entries[:2]...)
+	for i := len(entries) - 1; i >= 2; i-- {
+		reversed = append(reversed, entries[i])
+	}
+
+	want, err := seededPlacement(entries, 42, enums.Sha256FisherYatesShuffle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := seededPlacement(reversed, 42, enums.Sha256FisherYatesShuffle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("seededPlacement() of reordered entries = %v, want %v", got, want)
+	}
+	if want[0] != 1 || want[4] != 2 {
+		t.Errorf("seededPlacement() = %v, want seeds 1 and 2 in slots 0 and 4", want)
+	}
+}
+
+func TestHoldDrawChecksCommittedSeed(t *testing.T) {
+	const seedHash42 = "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049"
+	seed := int64(42)
+	otherSeed := int64(43)
+
+	repo, svc := newBracketFixture()
+	if _, err := svc.HoldDraw(1, seededEntries(4), &seed, enums.SingleElimination, bracketSettings()); !errors.Is(err, ErrDrawSeedNotCommitted) {
+		t.Errorf("HoldDraw() without a commitment error = %v, want %v", err, ErrDrawSeedNotCommitted)
+	}
+
+	if _, err := svc.CommitDrawSeed(1, "not a hash", "Referee"); !errors.Is(err, ErrInvalidSeedHash) {
+		t.Errorf("CommitDrawSeed() error = %v, want %v", err, ErrInvalidSeedHash)
+	}
+	if _, err := svc.CommitDrawSeed(1, seedHash42, "Referee"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CommitDrawSeed(1, seedHash42, "Referee"); !errors.Is(err, ErrDrawSeedAlreadyCommitted) {
+		t.Errorf("second CommitDrawSeed() error = %v, want %v", err, ErrDrawSeedAlreadyCommitted)
+	}
+	for _, entry := range seededEntries(4) {
+		repo.AddEventEntry(&db.EventEntry{EventId: 1, OpponentId: entry.OpponentId, Seed: entry.Seed})
+	}
+
+	if _, err := svc.HoldDraw(1, seededEntries(4), nil, enums.SingleElimination, bracketSettings()); !errors.Is(err, ErrDrawSeedNotRevealed) {
+		t.Errorf("HoldDraw() without the seed error = %v, want %v", err, ErrDrawSeedNotRevealed)
+	}
+	if _, err := svc.HoldDraw(1, seededEntries(4), &otherSeed, enums.SingleElimination, bracketSettings()); !errors.Is(err, ErrDrawSeedMismatch) {
+		t.Errorf("HoldDraw() with another seed error = %v, want %v", err, ErrDrawSeedMismatch)
+	}
+	if _, err := svc.HoldDraw(1, seededEntries(3), &seed, enums.SingleElimination, bracketSettings()); !errors.Is(err, ErrDrawEntriesNotRegistered) {
+		t.Errorf("HoldDraw() with other entries error = %v, want %v", err, ErrDrawEntriesNotRegistered)
+	}
+	reseeded := seededEntries(4)
+	reseeded[0].Seed, reseeded[1].Seed = 2, 1
+	if _, err := svc.HoldDraw(1, reseeded, &seed, enums.SingleElimination, bracketSettings()); !errors.Is(err, ErrDrawEntriesNotRegistered) {
+		t.Errorf("HoldDraw() with other seeds error = %v, want %v", err, ErrDrawEntriesNotRegistered)
+	}
+	if len(repo.matches) != 0 {
+		t.Fatalf("%d matches created by rejected draws", len(repo.matches))
+	}
+
+	record, err := svc.HoldDraw(1, seededEntries(4), &seed, enums.SingleElimination, bracketSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Shuffle != enums.Sha256FisherYatesShuffle {
+		t.Errorf("draw shuffle = %s, want %s", record.Shuffle, enums.Sha256FisherYatesShuffle)
+	}
+	if record.Commitment == nil || record.Commitment.CommittedBy != "Referee" {
+		t.Errorf("draw commitment = %+v, want committed by Referee", record.Commitment)
+	}
+
+	verification, err := svc.VerifyDraw(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verification.Mismatches) != 0 {
+		t.Errorf("VerifyDraw() mismatches = %v", verification.Mismatches)
+	}
+}
+
+func TestCommitDrawSeedRejectedOnceEntriesAdded(t *testing.T) {
+	const seedHash42 = "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049"
+
+	repo, svc := newBracketFixture()
+	repo.AddEventEntry(&db.EventEntry{EventId: 1, OpponentId: 1, Seed: 1})
+
+	if _, err := svc.CommitDrawSeed(1, seedHash42, "Referee"); !errors.Is(err, ErrDrawSeedAfterEntries) {
+		t.Errorf("CommitDrawSeed() after entries error = %v, want %v", err, ErrDrawSeedAfterEntries)
+	}
+	if _, err := repo.GetDrawCommitment(1); err == nil {
+		t.Error("commitment stored after entries were added")
+	}
+}
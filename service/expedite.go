@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+)
+
+// ExpediteTimeLimit is how long a set may run before the expedite system is
+// introduced, unless at least ExpediteMinimumPoints have been scored.
+const ExpediteTimeLimit = 10 * time.Minute
+const ExpediteMinimumPoints = 18
+
+// ExpediteReturnLimit is the number of successful returns after which the
+// receiver wins the point while the expedite system is in operation.
+const ExpediteReturnLimit = 13
+
+var ErrExpediteAlreadyStarted = errors.New("expedite already started")
+var ErrExpediteNotStarted = errors.New("expedite not started")
+var ErrExpediteTooLate = errors.New("expedite cannot be introduced once 18 points have been scored")
+
+// StartExpedite introduces the expedite system from the next point of the set,
+// unless ExpediteMinimumPoints have been scored in it already. Once introduced
+// it remains in operation until the end of the match.
+func (s *service) StartExpedite(matchId int, setId int) error {
+	return s.inTx(func(tx *service) error {
+		match, set, err := tx.getOpenSet(matchId, setId)
+		if err != nil {
+			return err
+		}
+		if set.ExpediteFromPoint != nil {
+			return ErrExpediteAlreadyStarted
+		}
+
+		played := pointsPlayed(*match, *set)
+		if played >= ExpediteMinimumPoints {
+			return ErrExpediteTooLate
+		}
+		set.ExpediteFromPoint = &played
+
+		return tx.repo.UpdateSet(set)
+	})
+}
+
+// AwardExpediteReturnPoint scores a point for the receiver once the receiver
+// has made ExpediteReturnLimit successful returns.
+func (s *service) AwardExpediteReturnPoint(matchId int, setId int) ([]int, error) {
+	return s.inTxChanging(func(tx *service) error {
+		match, set, err := tx.getOpenSet(matchId, setId)
+		if err != nil {
+			return err
+		}
+		if set.ExpediteFromPoint == nil {
+			return ErrExpediteNotStarted
+		}
+		err = tx.repo.ClearScoreRedos(matchId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.scorePoint(match, set, !nextServerIsA(*match, *set))
+		if err != nil {
+			return err
+		}
+
+		return tx.handleChangeOfEnds(match, set, false)
+	})
+}
+
+// startExpediteIfOverdue introduces the expedite system when the set has
+// outrun ExpediteTimeLimit without enough points being scored. It is applied
+// both before scoring a point and when reading the match, so the system shows
+// as in operation as soon as the limit is reached.
+func startExpediteIfOverdue(match db.Match, set *db.Set, now time.Time) {
+	if set.ExpediteFromPoint != nil || now.Sub(set.StartedAt) < ExpediteTimeLimit {
+		return
+	}
+	played := pointsPlayed(match, *set)
+	if played >= ExpediteMinimumPoints {
+		return
+	}
+	set.ExpediteFromPoint = &played
+}
+
+func isExpedited(sets []db.Set) bool {
+	for _, set := range sets {
+		if set.ExpediteFromPoint != nil {
+			return true
+		}
+	}
+	return false
+}
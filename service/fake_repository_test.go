@@ -0,0 +1,654 @@
+package service
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+)
+
+// fakeRepository keeps the tables of a few matches in memory. Calls to any
+// repository method it does not implement panic through the nil embedded
+// interface. A transaction that fails is rolled back, apart from the ids it
+// used up, as a sequence would not give them out again either.
+type fakeRepository struct {
+	db.Repository
+
+	now              time.Time
+	lastId           int
+	inTx             bool
+	tournaments      []db.Tournament
+	tournamentEvents []db.Event
+	entries          []db.EventEntry
+	registered       []db.Player
+	teams            []db.Team
+	ties             []db.Tie
+	matches          []db.Match
+	players          []db.PlayerInfoByMatchIdRow
+	teamRows         []db.TeamInfoByMatchIdRow
+	sets             []db.Set
+	setLogs          []db.SetLog
+	events           []db.MatchEvent
+	redos            []db.ScoreRedo
+	ratings          []db.Rating
+	ratingChanges    []db.RatingChange
+	seasons          []db.Season
+	seasonEntries    []db.SeasonEntry
+	readiness        []db.FixtureReadiness
+	draws            []db.Draw
+	drawEntries      []db.DrawEntry
+	commitments      []db.DrawCommitment
+	formatTemplates  []db.MatchFormatTemplate
+}
+
+func newFakeRepository(matches ...db.Match) *fakeRepository {
+	r := &fakeRepository{now: time.Date(2023, 11, 1, 10, 0, 0, 0, time.UTC)}
+	for _, match := range matches {
+		r.matches = append(r.matches, match)
+		if match.Id > r.lastId {
+			r.lastId = match.Id
+		}
+	}
+	return r
+}
+
+// tick moves the clock on, as every statement of a new transaction would see
+// a later now().
+func (r *fakeRepository) tick() time.Time {
+	r.now = r.now.Add(time.Second)
+	return r.now
+}
+
+func (r *fakeRepository) nextId() int {
+	r.lastId += 1
+	return r.lastId
+}
+
+func (r *fakeRepository) findMatch(id int) *db.Match {
+	for i := range r.matches {
+		if r.matches[i].Id == id {
+			return &r.matches[i]
+		}
+	}
+	return nil
+}
+
+// opponentsOf returns the ids of the opponents in slot A and slot B of the
+// match, with 0 for an empty slot.
+func (r *fakeRepository) opponentsOf(matchId int) [2]int {
+	var ids [2]int
+	for _, row := range r.players {
+		if row.MatchId != matchId {
+			continue
+		}
+		if row.IsOpponentA {
+			ids[0] = row.PlayerId
+		} else {
+			ids[1] = row.PlayerId
+		}
+	}
+	return ids
+}
+
+func (r *fakeRepository) RunInTx(fn func(repo db.Repository) error) error {
+	if r.inTx {
+		return fn(r)
+	}
+
+	r.tick()
+	saved := r.snapshot()
+	r.inTx = true
+	err := fn(r)
+	r.inTx = false
+	if err != nil {
+		saved.now = r.now
+		saved.lastId = r.lastId
+		*r = *saved
+	}
+	return err
+}
+
+// snapshot copies the repository with tables of its own, so that changes to
+// the rows of r leave the copy as it was.
+func (r *fakeRepository) snapshot() *fakeRepository {
+	saved := *r
+	saved.tournaments = cloneRows(r.tournaments)
+	saved.tournamentEvents = cloneRows(r.tournamentEvents)
+	saved.entries = cloneRows(r.entries)
+	saved.registered = cloneRows(r.registered)
+	saved.teams = cloneRows(r.teams)
+	saved.ties = cloneRows(r.ties)
+	saved.matches = cloneRows(r.matches)
+	saved.players = cloneRows(r.players)
+	saved.teamRows = cloneRows(r.teamRows)
+	saved.sets = cloneRows(r.sets)
+	saved.setLogs = cloneRows(r.setLogs)
+	saved.events = cloneRows(r.events)
+	saved.redos = cloneRows(r.redos)
+	saved.ratings = cloneRows(r.ratings)
+	saved.ratingChanges = cloneRows(r.ratingChanges)
+	saved.seasons = cloneRows(r.seasons)
+	saved.seasonEntries = cloneRows(r.seasonEntries)
+	saved.readiness = cloneRows(r.readiness)
+	saved.draws = cloneRows(r.draws)
+	saved.drawEntries = cloneRows(r.drawEntries)
+	saved.commitments = cloneRows(r.commitments)
+	saved.formatTemplates = cloneRows(r.formatTemplates)
+	return &saved
+}
+
+func cloneRows[T any](rows []T) []T {
+	if rows == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(rows)), rows...)
+}
+
+func (r *fakeRepository) CreateTournament(tournament *db.Tournament) (int64, error) {
+	for _, existing := range r.tournaments {
+		if existing.Name == tournament.Name {
+			return 0, db.ErrUniqueViolation
+		}
+	}
+	created := *tournament
+	created.Id = r.nextId()
+	r.tournaments = append(r.tournaments, created)
+	return int64(created.Id), nil
+}
+
+func (r *fakeRepository) GetEventById(id int) (*db.Event, error) {
+	for _, event := range r.tournamentEvents {
+		if event.Id == id {
+			return &event, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetMatchFormatTemplateByName(name string) (*db.MatchFormatTemplate, error) {
+	for _, template := range r.formatTemplates {
+		if template.Name == name {
+			return &template, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) CreateMatch(match *db.Match) (int64, error) {
+	created := *match
+	created.Id = r.nextId()
+	r.matches = append(r.matches, created)
+	return int64(created.Id), nil
+}
+
+func (r *fakeRepository) GetAllMatches(matches *[]db.Match, statusFilter string, tournamentId *int, eventId *int) error {
+	for _, match := range r.matches {
+		if eventId != nil && (match.EventId == nil || *match.EventId != *eventId) {
+			continue
+		}
+		*matches = append(*matches, match)
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetPlayerById(id int) (*db.Player, error) {
+	for _, player := range r.registered {
+		if player.Id == id {
+			return &player, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetPlayersByName(name string) ([]db.Player, error) {
+	players := make([]db.Player, 0)
+	for _, player := range r.registered {
+		if player.Name == name {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
+func (r *fakeRepository) GetTournamentById(id int) (*db.Tournament, error) {
+	for _, tournament := range r.tournaments {
+		if tournament.Id == id {
+			return &tournament, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) CreateTeam(team *db.Team) error {
+	created := *team
+	created.Id = r.nextId()
+	r.teams = append(r.teams, created)
+	return nil
+}
+
+func (r *fakeRepository) GetTeamById(id int) (*db.Team, error) {
+	for _, team := range r.teams {
+		if team.Id == id {
+			return &team, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) AddEventEntry(entry *db.EventEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeRepository) GetEventEntries(eventId int) ([]db.EventEntry, error) {
+	entries := make([]db.EventEntry, 0)
+	for _, entry := range r.entries {
+		if entry.EventId == eventId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeRepository) LockTieById(id int) (*db.Tie, error) {
+	for _, tie := range r.ties {
+		if tie.Id == id {
+			return &tie, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) UpdateTie(tie *db.Tie) error {
+	for i := range r.ties {
+		if r.ties[i].Id == tie.Id {
+			r.ties[i] = *tie
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetMatchesByTieId(tieId int) ([]db.Match, error) {
+	matches := make([]db.Match, 0)
+	for _, match := range r.matches {
+		if match.TieId != nil && *match.TieId == tieId {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func (r *fakeRepository) LockMatchById(id int) (*db.Match, error) {
+	return r.GetMatchById(id)
+}
+
+func (r *fakeRepository) GetMatchById(id int) (*db.Match, error) {
+	match := r.findMatch(id)
+	if match == nil {
+		return nil, sql.ErrNoRows
+	}
+	found := *match
+	return &found, nil
+}
+
+func (r *fakeRepository) UpdateMatchStatus(matchId int, status string) error {
+	r.findMatch(matchId).Status = status
+	return nil
+}
+
+func (r *fakeRepository) UpdateMatchResult(matchId int, result string) error {
+	r.findMatch(matchId).Result = result
+	return nil
+}
+
+func (r *fakeRepository) AddPlayerToMatch(mapping *db.PlayerMatchMapping) error {
+	row := db.PlayerInfoByMatchIdRow{
+		MatchId:     mapping.MatchId,
+		PlayerId:    mapping.PlayerId,
+		IsOpponentA: mapping.IsOpponentA,
+	}
+	if player, err := r.GetPlayerById(mapping.PlayerId); err == nil {
+		row.PlayerName = player.Name
+		row.TournamentId = player.TournamentId
+	}
+	r.players = append(r.players, row)
+	return nil
+}
+
+func (r *fakeRepository) RemoveOpponentFromMatch(match *db.Match, isOppA bool) error {
+	players := r.players[:0]
+	for _, row := range r.players {
+		if row.MatchId != match.Id || row.IsOpponentA != isOppA {
+			players = append(players, row)
+		}
+	}
+	r.players = players
+	return nil
+}
+
+func (r *fakeRepository) GetPlayerInfoByMatchId(matchId int) ([]db.PlayerInfoByMatchIdRow, error) {
+	rows := make([]db.PlayerInfoByMatchIdRow, 0)
+	for _, row := range r.players {
+		if row.MatchId == matchId {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].IsOpponentA && !rows[j].IsOpponentA })
+	return rows, nil
+}
+
+func (r *fakeRepository) GetTeamInfoByMatchId(matchId int) ([]db.TeamInfoByMatchIdRow, error) {
+	rows := make([]db.TeamInfoByMatchIdRow, 0)
+	for _, row := range r.teamRows {
+		if row.MatchId == matchId {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].IsOpponentA && !rows[j].IsOpponentA })
+	return rows, nil
+}
+
+func (r *fakeRepository) UpdateMatchWinner(match *db.Match, isOppA bool) error {
+	for i := range r.players {
+		if r.players[i].MatchId == match.Id {
+			r.players[i].IsWinner = r.players[i].IsOpponentA == isOppA
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) ResetMatchWinner(match *db.Match) error {
+	for i := range r.players {
+		if r.players[i].MatchId == match.Id {
+			r.players[i].IsWinner = false
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) CreateSet(set *db.Set) (int64, error) {
+	set.Id = r.nextId()
+	r.sets = append(r.sets, *set)
+	return int64(set.Id), nil
+}
+
+func (r *fakeRepository) UpdateSet(set *db.Set) error {
+	for i := range r.sets {
+		if r.sets[i].Id == set.Id && r.sets[i].Version == set.Version {
+			set.Version += 1
+			r.sets[i] = *set
+			return nil
+		}
+	}
+	return db.ErrVersionConflict
+}
+
+func (r *fakeRepository) DeleteSet(id int) error {
+	sets := r.sets[:0]
+	for _, set := range r.sets {
+		if set.Id != id {
+			sets = append(sets, set)
+		}
+	}
+	r.sets = sets
+	return nil
+}
+
+func (r *fakeRepository) GetSetsByMatchId(id int) ([]db.Set, error) {
+	sets := make([]db.Set, 0)
+	for _, set := range r.sets {
+		if set.MatchId == id {
+			sets = append(sets, set)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].SetNumber < sets[j].SetNumber })
+	return sets, nil
+}
+
+func (r *fakeRepository) CreateSetLog(setLog *db.SetLog) error {
+	setLog.Id = r.nextId()
+	setLog.CreatedAt = r.now
+	r.setLogs = append(r.setLogs, *setLog)
+	return nil
+}
+
+func (r *fakeRepository) DeleteSetLog(id int) error {
+	setLogs := r.setLogs[:0]
+	for _, sl := range r.setLogs {
+		if sl.Id != id {
+			setLogs = append(setLogs, sl)
+		}
+	}
+	r.setLogs = setLogs
+	return nil
+}
+
+func (r *fakeRepository) GetSetLogsBySetId(setId int, limit *int) ([]db.SetLog, error) {
+	setLogs := make([]db.SetLog, 0)
+	for i := len(r.setLogs) - 1; i >= 0; i-- {
+		if r.setLogs[i].SetId != setId {
+			continue
+		}
+		if limit != nil && len(setLogs) == *limit {
+			break
+		}
+		setLogs = append(setLogs, r.setLogs[i])
+	}
+	return setLogs, nil
+}
+
+func (r *fakeRepository) CreateMatchEvent(event *db.MatchEvent) error {
+	event.Id = r.nextId()
+	event.CreatedAt = r.now
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeRepository) GetMatchEventsByMatchId(matchId int) ([]db.MatchEvent, error) {
+	events := make([]db.MatchEvent, len(r.events))
+	copy(events, r.events)
+	return events, nil
+}
+
+func (r *fakeRepository) DeleteMatchEventsBySetId(setId int, eventType *string) error {
+	events := r.events[:0]
+	for _, e := range r.events {
+		if e.SetId != nil && *e.SetId == setId && (eventType == nil || e.Type == *eventType) {
+			continue
+		}
+		events = append(events, e)
+	}
+	r.events = events
+	return nil
+}
+
+func (r *fakeRepository) MoveMatchEventsToSet(fromSetId int, toSetId int) error {
+	for i := range r.events {
+		if r.events[i].SetId != nil && *r.events[i].SetId == fromSetId {
+			setId := toSetId
+			r.events[i].SetId = &setId
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) PushScoreRedo(redo *db.ScoreRedo) error {
+	r.redos = append(r.redos, *redo)
+	return nil
+}
+
+func (r *fakeRepository) PopScoreRedo(matchId int) (*db.ScoreRedo, error) {
+	if len(r.redos) == 0 {
+		return nil, nil
+	}
+	redo := r.redos[len(r.redos)-1]
+	r.redos = r.redos[:len(r.redos)-1]
+	return &redo, nil
+}
+
+func (r *fakeRepository) ClearScoreRedos(matchId int) error {
+	r.redos = nil
+	return nil
+}
+
+func (r *fakeRepository) LockRating(format string, playerId int, partnerId int, initialRating float64) (*db.Rating, error) {
+	for _, rating := range r.ratings {
+		if rating.Format == format && rating.PlayerId == playerId && rating.PartnerId == partnerId {
+			return &rating, nil
+		}
+	}
+	rating := db.Rating{Format: format, PlayerId: playerId, PartnerId: partnerId, Rating: initialRating}
+	r.ratings = append(r.ratings, rating)
+	return &rating, nil
+}
+
+func (r *fakeRepository) UpdateRating(rating *db.Rating) error {
+	for i := range r.ratings {
+		if r.ratings[i].Format == rating.Format && r.ratings[i].PlayerId == rating.PlayerId && r.ratings[i].PartnerId == rating.PartnerId {
+			r.ratings[i] = *rating
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) CreateRatingChange(change *db.RatingChange) error {
+	created := *change
+	created.Id = r.nextId()
+	created.CreatedAt = r.now
+	r.ratingChanges = append(r.ratingChanges, created)
+	return nil
+}
+
+func (r *fakeRepository) GetRatingChangesByMatchId(matchId int) ([]db.RatingChange, error) {
+	changes := make([]db.RatingChange, 0)
+	for _, change := range r.ratingChanges {
+		if change.MatchId == matchId {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (r *fakeRepository) DeleteRatingChangesByMatchId(matchId int) error {
+	changes := r.ratingChanges[:0]
+	for _, change := range r.ratingChanges {
+		if change.MatchId != matchId {
+			changes = append(changes, change)
+		}
+	}
+	r.ratingChanges = changes
+	return nil
+}
+
+func (r *fakeRepository) CreateDraw(draw *db.Draw) (int64, error) {
+	created := *draw
+	created.Id = r.nextId()
+	created.CreatedAt = r.now
+	r.draws = append(r.draws, created)
+	return int64(created.Id), nil
+}
+
+func (r *fakeRepository) AddDrawEntry(entry *db.DrawEntry) error {
+	r.drawEntries = append(r.drawEntries, *entry)
+	return nil
+}
+
+func (r *fakeRepository) GetDrawByEventId(eventId int) (*db.Draw, error) {
+	for _, draw := range r.draws {
+		if draw.EventId == eventId {
+			return &draw, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetDrawEntries(drawId int) ([]db.DrawEntry, error) {
+	entries := make([]db.DrawEntry, 0)
+	for _, entry := range r.drawEntries {
+		if entry.DrawId == drawId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeRepository) CreateDrawCommitment(commitment *db.DrawCommitment) error {
+	if _, err := r.GetDrawCommitment(commitment.EventId); err == nil {
+		return db.ErrUniqueViolation
+	}
+	created := *commitment
+	created.CreatedAt = r.now
+	r.commitments = append(r.commitments, created)
+	return nil
+}
+
+func (r *fakeRepository) GetDrawCommitment(eventId int) (*db.DrawCommitment, error) {
+	for _, commitment := range r.commitments {
+		if commitment.EventId == eventId {
+			return &commitment, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetSeasonById(id int) (*db.Season, error) {
+	for _, season := range r.seasons {
+		if season.Id == id {
+			return &season, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetSeasonEntries(seasonId int) ([]db.SeasonEntry, error) {
+	entries := make([]db.SeasonEntry, 0)
+	for _, entry := range r.seasonEntries {
+		if entry.SeasonId == seasonId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeRepository) GetMatchesBySeasonId(seasonId int) ([]db.Match, error) {
+	matches := make([]db.Match, 0)
+	for _, match := range r.matches {
+		if match.SeasonId != nil && *match.SeasonId == seasonId {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func (r *fakeRepository) GetOverdueSeasonMatches(now time.Time) ([]db.Match, error) {
+	matches := make([]db.Match, 0)
+	for _, match := range r.matches {
+		if match.SeasonId != nil && match.Status == "UPCOMING" && match.Deadline != nil && match.Deadline.Before(now) {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+func (r *fakeRepository) ReportFixtureReady(readiness *db.FixtureReadiness) error {
+	for _, row := range r.readiness {
+		if row.MatchId == readiness.MatchId && row.OpponentId == readiness.OpponentId {
+			return nil
+		}
+	}
+	row := *readiness
+	row.ReportedAt = r.now
+	r.readiness = append(r.readiness, row)
+	return nil
+}
+
+func (r *fakeRepository) GetFixtureReadiness(matchId int) ([]db.FixtureReadiness, error) {
+	readiness := make([]db.FixtureReadiness, 0)
+	for _, row := range r.readiness {
+		if row.MatchId == matchId {
+			readiness = append(readiness, row)
+		}
+	}
+	return readiness, nil
+}
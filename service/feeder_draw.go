@@ -0,0 +1,176 @@
+package service
+
+import (
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+// bracketFeed is a main bracket match whose loser moves into a consolation
+// draw or losers bracket. Rounds count from 1 for the first round.
+type bracketFeed struct {
+	Round    int
+	Position int
+}
+
+// bracketSlot is opponent A or B of a match.
+type bracketSlot struct {
+	MatchId int
+	IsA     bool
+}
+
+// drawNode is a match of a draw fed by main bracket losers. Each of its two
+// opponents is either the winner of a child match or the loser of a main
+// bracket match.
+type drawNode struct {
+	Round    int
+	Position int
+	Children [2]*drawNode
+	Feeds    [2]*bracketFeed
+}
+
+// consolationDraw pairs up the losers of the first round of a main bracket
+// with size slots, returning the final of the draw. A bracket with a single
+// first round match has no consolation draw.
+func consolationDraw(size int) *drawNode {
+	if size < 4 {
+		return nil
+	}
+
+	nodes := make([]*drawNode, 0, size/4)
+	for i := 0; i < size/4; i++ {
+		nodes = append(nodes, &drawNode{
+			Round:    1,
+			Position: i,
+			Feeds:    [2]*bracketFeed{{Round: 1, Position: 2 * i}, {Round: 1, Position: 2*i + 1}},
+		})
+	}
+	for round := 2; len(nodes) > 1; round++ {
+		nodes = pairDrawNodes(nodes, round)
+	}
+	return nodes[0]
+}
+
+// losersDraw builds the losers bracket of a double elimination with
+// roundCount main bracket rounds, returning its final. First round losers
+// play each other, and the winners then alternate between meeting the losers
+// of the next main bracket round and playing each other. The losers of each
+// main bracket round are drawn in reverse order to put off rematches.
+func losersDraw(roundCount int) *drawNode {
+	size := 1 << roundCount
+
+	nodes := make([]*drawNode, 0, size/4)
+	for i := 0; i < size/4; i++ {
+		nodes = append(nodes, &drawNode{
+			Round:    1,
+			Position: i,
+			Feeds:    [2]*bracketFeed{{Round: 1, Position: 2 * i}, {Round: 1, Position: 2*i + 1}},
+		})
+	}
+
+	round := 2
+	for mainRound := 2; mainRound <= roundCount; mainRound++ {
+		count := len(nodes)
+		for i := range nodes {
+			nodes[i] = &drawNode{
+				Round:    round,
+				Position: i,
+				Children: [2]*drawNode{nodes[i], nil},
+				Feeds:    [2]*bracketFeed{nil, {Round: mainRound, Position: count - 1 - i}},
+			}
+		}
+		round += 1
+
+		if mainRound < roundCount {
+			nodes = pairDrawNodes(nodes, round)
+			round += 1
+		}
+	}
+	return nodes[0]
+}
+
+func pairDrawNodes(nodes []*drawNode, round int) []*drawNode {
+	paired := make([]*drawNode, 0, len(nodes)/2)
+	for i := 0; i < len(nodes); i += 2 {
+		paired = append(paired, &drawNode{
+			Round:    round,
+			Position: i / 2,
+			Children: [2]*drawNode{nodes[i], nodes[i+1]},
+		})
+	}
+	return paired
+}
+
+// feederDraw creates the matches of a draw fed by main bracket losers. A
+// match that can only ever get one opponent, because of byes in the main
+// bracket, is left out and that opponent moves straight on.
+type feederDraw struct {
+	svc                *service
+	template           db.Match
+	bracket            enums.BracketType
+	playedInFirstRound func(position int) bool
+	loserSlots         map[bracketFeed]*bracketSlot
+}
+
+// create creates the match of node and the matches feeding it. The winner
+// moves on to target, which is nil for the final of the draw.
+func (d *feederDraw) create(node *drawNode, depth int, target *bracketSlot) error {
+	if node == nil {
+		return nil
+	}
+
+	live := make([]int, 0, 2)
+	for i := range node.Feeds {
+		if d.isLive(node, i) {
+			live = append(live, i)
+		}
+	}
+	switch len(live) {
+	case 0:
+		return nil
+	case 1:
+		return d.feed(node, live[0], depth, target)
+	}
+
+	round := node.Round
+	position := node.Position
+	match := d.template
+	match.Stage = string(enums.Knockout)
+	if d.bracket == enums.ConsolationBracket {
+		match.Stage = string(knockoutStage(1 << depth))
+	}
+	match.Bracket = string(d.bracket)
+	match.BracketRound = &round
+	match.BracketPosition = &position
+	if target != nil {
+		match.NextMatchId = &target.MatchId
+		match.NextMatchSlotIsA = &target.IsA
+	}
+
+	id, err := d.svc.repo.CreateMatch(&match)
+	if err != nil {
+		return err
+	}
+	for i := range node.Feeds {
+		if err := d.feed(node, i, depth+1, &bracketSlot{MatchId: int(id), IsA: i == 0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *feederDraw) feed(node *drawNode, i int, depth int, target *bracketSlot) error {
+	if node.Feeds[i] != nil {
+		d.loserSlots[*node.Feeds[i]] = target
+		return nil
+	}
+	return d.create(node.Children[i], depth, target)
+}
+
+// isLive reports whether opponent i of node can ever be decided.
+func (d *feederDraw) isLive(node *drawNode, i int) bool {
+	if feed := node.Feeds[i]; feed != nil {
+		return feed.Round > 1 || d.playedInFirstRound(feed.Position)
+	}
+	child := node.Children[i]
+	return child != nil && (d.isLive(child, 0) || d.isLive(child, 1))
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+// feederSlotAt is the round, position and slot of a feeder draw match.
+type feederSlotAt struct {
+	Round    int
+	Position int
+	IsA      bool
+}
+
+func TestFeederDrawLoserSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     int
+		drawType    enums.DrawType
+		bracket     enums.BracketType
+		feederCount int
+		want        map[bracketFeed]feederSlotAt
+	}{
+		{
+			name:        "double elimination with 4 entries",
+			entries:     4,
+			drawType:    enums.DoubleElimination,
+			bracket:     enums.LosersBracket,
+			feederCount: 2,
+			want: map[bracketFeed]feederSlotAt{
+				{1, 0}: {1, 0, true},
+				{1, 1}: {1, 0, false},
+				{2, 0}: {2, 0, false},
+			},
+		},
+		{
+			name:        "double elimination with 6 entries skips the matches of byes",
+			entries:     6,
+			drawType:    enums.DoubleElimination,
+			bracket:     enums.LosersBracket,
+			feederCount: 4,
+			want: map[bracketFeed]feederSlotAt{
+				{1, 1}: {2, 0, true},
+				{1, 3}: {2, 1, true},
+				{2, 0}: {2, 1, false},
+				{2, 1}: {2, 0, false},
+				{3, 0}: {4, 0, false},
+			},
+		},
+		{
+			name:        "double elimination with 8 entries",
+			entries:     8,
+			drawType:    enums.DoubleElimination,
+			bracket:     enums.LosersBracket,
+			feederCount: 6,
+			want: map[bracketFeed]feederSlotAt{
+				{1, 0}: {1, 0, true},
+				{1, 1}: {1, 0, false},
+				{1, 2}: {1, 1, true},
+				{1, 3}: {1, 1, false},
+				{2, 0}: {2, 1, false},
+				{2, 1}: {2, 0, false},
+				{3, 0}: {4, 0, false},
+			},
+		},
+		{
+			name:        "consolation with 5 entries has a single first round loser",
+			entries:     5,
+			drawType:    enums.ConsolationDraw,
+			bracket:     enums.ConsolationBracket,
+			feederCount: 0,
+			want:        map[bracketFeed]feederSlotAt{},
+		},
+		{
+			name:        "consolation with 6 entries",
+			entries:     6,
+			drawType:    enums.ConsolationDraw,
+			bracket:     enums.ConsolationBracket,
+			feederCount: 1,
+			want: map[bracketFeed]feederSlotAt{
+				{1, 1}: {2, 0, true},
+				{1, 3}: {2, 0, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, svc := newBracketFixture()
+			if err := svc.createBracket(1, placeBySeed(seededEntries(tt.entries)), tt.drawType, bracketSettings()); err != nil {
+				t.Fatal(err)
+			}
+
+			matches := make(map[int]db.Match)
+			for _, match := range repo.matches {
+				matches[match.Id] = match
+			}
+
+			got := make(map[bracketFeed]feederSlotAt)
+			feederCount := 0
+			for _, match := range repo.matches {
+				switch enums.BracketType(match.Bracket) {
+				case tt.bracket:
+					feederCount += 1
+				case enums.MainBracket:
+					if match.LoserNextMatchId == nil {
+						continue
+					}
+					next := matches[*match.LoserNextMatchId]
+					if next.Bracket != string(tt.bracket) {
+						t.Errorf("main match %d/%d loser moves to the %s bracket", *match.BracketRound, *match.BracketPosition, next.Bracket)
+					}
+					got[bracketFeed{Round: *match.BracketRound, Position: *match.BracketPosition}] = feederSlotAt{
+						Round:    *next.BracketRound,
+						Position: *next.BracketPosition,
+						IsA:      *match.LoserNextMatchSlotIsA,
+					}
+				}
+			}
+
+			if feederCount != tt.feederCount {
+				t.Errorf("%d %s matches, want %d", feederCount, tt.bracket, tt.feederCount)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loser slots = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLosersBracketFeedsGrandFinal(t *testing.T) {
+	repo, svc := newBracketFixture()
+	if err := svc.createBracket(1, placeBySeed(seededEntries(8)), enums.DoubleElimination, bracketSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	var grandFinal db.Match
+	for _, match := range repo.matches {
+		if match.Bracket == string(enums.GrandFinalBracket) && *match.BracketRound == 1 {
+			grandFinal = match
+		}
+	}
+	if grandFinal.Id == 0 {
+		t.Fatal("no grand final created")
+	}
+
+	for _, match := range repo.matches {
+		if match.Bracket == string(enums.GrandFinalBracket) {
+			continue
+		}
+		if match.NextMatchId == nil {
+			t.Errorf("%s match %d/%d has no next match", match.Bracket, *match.BracketRound, *match.BracketPosition)
+			continue
+		}
+		if *match.NextMatchId != grandFinal.Id {
+			continue
+		}
+		wantSlotIsA := match.Bracket == string(enums.MainBracket)
+		if *match.NextMatchSlotIsA != wantSlotIsA {
+			t.Errorf("%s final moves to slot A %v of the grand final, want %v", match.Bracket, *match.NextMatchSlotIsA, wantSlotIsA)
+		}
+	}
+}
+
+func TestGrandFinalReset(t *testing.T) {
+	tests := []struct {
+		name      string
+		winnerIsA bool
+	}{
+		{"main bracket winner takes the grand final", true},
+		{"losers bracket winner forces a reset", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, svc := newBracketFixture()
+			if err := svc.createBracket(1, placeBySeed(seededEntries(4)), enums.DoubleElimination, bracketSettings()); err != nil {
+				t.Fatal(err)
+			}
+			playUntilGrandFinal(t, repo, svc)
+
+			var grandFinal, reset db.Match
+			for _, match := range repo.matches {
+				if match.Bracket != string(enums.GrandFinalBracket) {
+					continue
+				}
+				if *match.BracketRound == 1 {
+					grandFinal = match
+				} else {
+					reset = match
+				}
+			}
+			finalists := repo.opponentsOf(grandFinal.Id)
+			if finalists[0] != 1 || finalists[1] == 0 {
+				t.Fatalf("grand final opponents = %v, want seed 1 against the losers bracket winner", finalists)
+			}
+
+			changed, err := svc.EndMatch(grandFinal.Id, enums.Walkover, tt.winnerIsA)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []int{reset.Id}; !reflect.DeepEqual(changed, want) {
+				t.Errorf("EndMatch() changed = %v, want %v", changed, want)
+			}
+			resetMatch := repo.findMatch(reset.Id)
+			if tt.winnerIsA {
+				if resetMatch.Status != string(enums.Past) || resetMatch.Result != string(enums.Unplayed) {
+					t.Errorf("reset is %s %s, want closed as unplayed", resetMatch.Status, resetMatch.Result)
+				}
+			} else {
+				if resetMatch.Status != string(enums.Upcoming) {
+					t.Errorf("reset is %s, want upcoming", resetMatch.Status)
+				}
+				if got, want := repo.opponentsOf(reset.Id), [2]int{finalists[1], 1}; got != want {
+					t.Errorf("reset opponents = %v, want %v", got, want)
+				}
+			}
+
+			if _, err := svc.Undo(grandFinal.Id); err != nil {
+				t.Fatal(err)
+			}
+			resetMatch = repo.findMatch(reset.Id)
+			if resetMatch.Status != string(enums.Upcoming) || resetMatch.Result != "" {
+				t.Errorf("reset is %s %s after undo, want upcoming", resetMatch.Status, resetMatch.Result)
+			}
+			if got := repo.opponentsOf(reset.Id); got != [2]int{} {
+				t.Errorf("reset opponents after undo = %v, want none", got)
+			}
+		})
+	}
+}
+
+// playUntilGrandFinal walks over every match outside the grand final, the
+// opponent in slot A winning each, until none can be played.
+func playUntilGrandFinal(t *testing.T, repo *fakeRepository, svc *service) {
+	t.Helper()
+	for {
+		played := false
+		for _, match := range repo.matches {
+			opponents := repo.opponentsOf(match.Id)
+			if match.Bracket == string(enums.GrandFinalBracket) || match.Status != string(enums.Upcoming) ||
+				opponents[0] == 0 || opponents[1] == 0 {
+				continue
+			}
+			if _, err := svc.EndMatch(match.Id, enums.Walkover, true); err != nil {
+				t.Fatal(err)
+			}
+			played = true
+		}
+		if !played {
+			return
+		}
+	}
+}
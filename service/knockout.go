@@ -0,0 +1,409 @@
+package service
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrNotEnoughEntries = errors.New("a bracket needs at least two entries")
+var ErrDuplicateEntry = errors.New("opponent entered more than once")
+var ErrDuplicateSeed = errors.New("seed given to more than one entry")
+var ErrBracketAlreadyGenerated = errors.New("bracket already generated for the event")
+var ErrNextMatchStarted = errors.New("next match of the bracket already started")
+
+// BracketEntry is a player or team entered into a knockout bracket. Seed 1 is
+// the top seed and 0 leaves the entry unseeded.
+type BracketEntry struct {
+	OpponentId int
+	Seed       int
+}
+
+// GenerateKnockoutBracket creates every match of a knockout bracket for the
+// event. The bracket is sized to the next power of two and the missing
+// entries become byes against the top seeds, whose opponents move straight
+// into the second round. Seeds are placed so that seeds 1 and 2 can only meet
+// in the final, seeds 1 to 4 only in the semi finals, and so on. drawType
+// adds a consolation draw for first round losers or a losers bracket for a
+// double elimination, whose winner plays the main bracket winner in the grand
+// final. A grand final won by the losers bracket winner is the first loss of
+// the main bracket winner, so it is followed by a reset match between the
+// two that decides the event.
+func (s *service) GenerateKnockoutBracket(
+	eventId int,
+	entries []BracketEntry,
+	drawType enums.DrawType,
+	settings MatchSettings,
+) error {
+	return s.inTx(func(tx *service) error {
+		return tx.generateKnockoutBracket(eventId, entries, drawType, settings)
+	})
+}
+
+func (s *service) generateKnockoutBracket(
+	eventId int,
+	entries []BracketEntry,
+	drawType enums.DrawType,
+	settings MatchSettings,
+) error {
+	ordered, err := orderBySeed(entries)
+	if err != nil {
+		return err
+	}
+
+	return s.createBracket(eventId, placeBySeed(ordered), drawType, settings)
+}
+
+// placeBySeed returns the opponent in each slot of the first round for the
+// entries ordered by seed, with 0 for a bye.
+func placeBySeed(ordered []BracketEntry) []int {
+	slots := seedPositions(bracketSize(len(ordered)))
+	draw := make([]int, len(slots))
+	for i, seed := range slots {
+		if seed <= len(ordered) {
+			draw[i] = ordered[seed-1].OpponentId
+		}
+	}
+	return draw
+}
+
+// createBracket creates every match of a knockout bracket for the event.
+// draw holds the opponent in each slot of the first round, where 0 is a bye
+// that moves the opponent of the slot straight into the second round.
+func (s *service) createBracket(eventId int, draw []int, drawType enums.DrawType, settings MatchSettings) error {
+	event, err := s.getEvent(eventId)
+	if err != nil {
+		return err
+	}
+	matches := []db.Match{}
+	err = s.repo.GetAllMatches(&matches, "", nil, &eventId)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if match.BracketRound != nil {
+			return ErrBracketAlreadyGenerated
+		}
+	}
+
+	size := len(draw)
+	roundCount := bits.Len(uint(size)) - 1
+	if drawType == enums.DoubleElimination && roundCount < 2 {
+		return ErrNotEnoughEntries
+	}
+
+	settings.EventId = &eventId
+	format := enums.MatchFormat(event.Format)
+	template, err := s.newMatch(format, enums.Knockout, settings)
+	if err != nil {
+		return err
+	}
+
+	playedInFirstRound := func(position int) bool {
+		return draw[2*position] != 0 && draw[2*position+1] != 0
+	}
+
+	// The draws fed by losers of the main bracket are created first, so that
+	// every main bracket match can point at the match its loser moves on to.
+	var finalNext *bracketSlot
+	feeder := &feederDraw{
+		svc:                s,
+		template:           *template,
+		playedInFirstRound: playedInFirstRound,
+		loserSlots:         make(map[bracketFeed]*bracketSlot),
+	}
+	switch drawType {
+	case enums.ConsolationDraw:
+		feeder.bracket = enums.ConsolationBracket
+		err = feeder.create(consolationDraw(size), 0, nil)
+	case enums.DoubleElimination:
+		// The reset match is created up front and closed as unplayed if the
+		// main bracket winner takes the grand final.
+		grandFinalRound, resetRound, position := 1, 2, 0
+		reset := *template
+		reset.Stage = string(enums.Final)
+		reset.Bracket = string(enums.GrandFinalBracket)
+		reset.BracketRound = &resetRound
+		reset.BracketPosition = &position
+		var resetId int64
+		resetId, err = s.repo.CreateMatch(&reset)
+		if err != nil {
+			return err
+		}
+
+		resetMatchId := int(resetId)
+		winnerSlotIsA, loserSlotIsA := true, false
+		grandFinal := reset
+		grandFinal.BracketRound = &grandFinalRound
+		grandFinal.NextMatchId = &resetMatchId
+		grandFinal.NextMatchSlotIsA = &winnerSlotIsA
+		grandFinal.LoserNextMatchId = &resetMatchId
+		grandFinal.LoserNextMatchSlotIsA = &loserSlotIsA
+		var grandFinalId int64
+		grandFinalId, err = s.repo.CreateMatch(&grandFinal)
+		if err != nil {
+			return err
+		}
+		finalNext = &bracketSlot{MatchId: int(grandFinalId), IsA: true}
+		feeder.bracket = enums.LosersBracket
+		err = feeder.create(losersDraw(roundCount), 0, &bracketSlot{MatchId: int(grandFinalId), IsA: false})
+	}
+	if err != nil {
+		return err
+	}
+
+	// Main bracket matches are created from the final backwards so that
+	// every match can point at the match its winner moves on to.
+	var next []db.Match
+	rounds := make([][]db.Match, 0, roundCount)
+	for matchCount := 1; matchCount < size; matchCount *= 2 {
+		bracketRound := roundCount - len(rounds)
+		round := make([]db.Match, matchCount)
+		for position := range round {
+			if matchCount == size/2 && !playedInFirstRound(position) {
+				continue
+			}
+
+			bracketPosition := position
+			match := *template
+			match.Stage = string(knockoutStage(matchCount))
+			match.Bracket = string(enums.MainBracket)
+			match.BracketRound = &bracketRound
+			match.BracketPosition = &bracketPosition
+			if next != nil {
+				nextMatchId := next[position/2].Id
+				slotIsA := position%2 == 0
+				match.NextMatchId = &nextMatchId
+				match.NextMatchSlotIsA = &slotIsA
+			} else if finalNext != nil {
+				match.NextMatchId = &finalNext.MatchId
+				match.NextMatchSlotIsA = &finalNext.IsA
+			}
+			if slot := feeder.loserSlots[bracketFeed{Round: bracketRound, Position: position}]; slot != nil {
+				match.LoserNextMatchId = &slot.MatchId
+				match.LoserNextMatchSlotIsA = &slot.IsA
+			}
+
+			id, err := s.repo.CreateMatch(&match)
+			if err != nil {
+				return err
+			}
+			match.Id = int(id)
+			round[position] = match
+		}
+		rounds = append(rounds, round)
+		next = round
+	}
+
+	firstRound := rounds[len(rounds)-1]
+	for position := range firstRound {
+		for _, isOppA := range []bool{true, false} {
+			opponentId := draw[2*position]
+			if !isOppA {
+				opponentId = draw[2*position+1]
+			}
+			if opponentId == 0 {
+				continue
+			}
+
+			match := firstRound[position]
+			slotIsA := isOppA
+			if match.Id == 0 {
+				match = rounds[len(rounds)-2][position/2]
+				slotIsA = position%2 == 0
+			}
+			err := s.addOpponentToMatch(match, opponentId, slotIsA)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, round := range rounds {
+		for _, match := range round {
+			if match.Id == 0 {
+				continue
+			}
+			if err := s.checkOpponentsInEvent(match.Id, format, &eventId); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// bracketSize returns the smallest power of two that fits the entries.
+func bracketSize(entryCount int) int {
+	size := 2
+	for size < entryCount {
+		size *= 2
+	}
+	return size
+}
+
+// orderBySeed returns the seeded entries by seed followed by the unseeded
+// entries in the order they were given.
+func orderBySeed(entries []BracketEntry) ([]BracketEntry, error) {
+	if len(entries) < 2 {
+		return nil, ErrNotEnoughEntries
+	}
+
+	seeded := make([]BracketEntry, 0)
+	unseeded := make([]BracketEntry, 0)
+	opponents := make(map[int]bool)
+	seeds := make(map[int]bool)
+	for _, entry := range entries {
+		if opponents[entry.OpponentId] {
+			return nil, ErrDuplicateEntry
+		}
+		opponents[entry.OpponentId] = true
+
+		if entry.Seed <= 0 {
+			unseeded = append(unseeded, entry)
+			continue
+		}
+		if seeds[entry.Seed] {
+			return nil, ErrDuplicateSeed
+		}
+		seeds[entry.Seed] = true
+		seeded = append(seeded, entry)
+	}
+
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return seeded[i].Seed < seeded[j].Seed
+	})
+	return append(seeded, unseeded...), nil
+}
+
+// seedPositions returns the seed placed in each slot of a bracket of the
+// given size, which must be a power of two. Each round is built by pairing
+// every seed of the previous round with the seed that adds up to one more
+// than the number of slots.
+func seedPositions(size int) []int {
+	positions := []int{1}
+	for len(positions) < size {
+		count := len(positions) * 2
+		nextPositions := make([]int, 0, count)
+		for _, seed := range positions {
+			nextPositions = append(nextPositions, seed, count+1-seed)
+		}
+		positions = nextPositions
+	}
+	return positions
+}
+
+// knockoutStage names a round by the number of matches played in it.
+func knockoutStage(matchCount int) enums.MatchStage {
+	switch matchCount {
+	case 1:
+		return enums.Final
+	case 2:
+		return enums.SemiFinal
+	case 4:
+		return enums.QuarterFinal
+	default:
+		return enums.Knockout
+	}
+}
+
+// advanceOpponents moves the winner of a bracket match into its slot of the
+// next match, and the loser into its slot of the consolation draw or the
+// losers bracket. The reset match of a grand final won by the main bracket
+// winner, who plays it as opponent A, is closed as unplayed instead.
+func (s *service) advanceOpponents(match *db.Match, winnerIsA bool) error {
+	if isGrandFinalWithReset(*match) && winnerIsA {
+		err := s.closeUnplayed(*match.NextMatchId)
+		if err != nil {
+			return err
+		}
+		s.markChanged(*match.NextMatchId)
+		return nil
+	}
+
+	opponentIds, err := s.opponentIdsFromMatch(*match)
+	if err != nil {
+		return err
+	}
+
+	moves := []struct {
+		nextMatchId *int
+		slotIsA     *bool
+		isOppA      bool
+	}{
+		{match.NextMatchId, match.NextMatchSlotIsA, winnerIsA},
+		{match.LoserNextMatchId, match.LoserNextMatchSlotIsA, !winnerIsA},
+	}
+	for _, move := range moves {
+		if move.nextMatchId == nil || move.slotIsA == nil {
+			continue
+		}
+		opponentId, ok := opponentIds[move.isOppA]
+		if !ok {
+			continue
+		}
+
+		next, err := s.repo.LockMatchById(*move.nextMatchId)
+		if err != nil {
+			return err
+		}
+		if err := s.addOpponentToMatch(*next, opponentId, *move.slotIsA); err != nil {
+			return err
+		}
+		s.markChanged(next.Id)
+	}
+	return nil
+}
+
+// withdrawOpponents undoes advanceOpponents.
+func (s *service) withdrawOpponents(match *db.Match) error {
+	if isGrandFinalWithReset(*match) {
+		reset, err := s.repo.LockMatchById(*match.NextMatchId)
+		if err != nil {
+			return err
+		}
+		if reset.Result == string(enums.Unplayed) {
+			err = s.reopenUnplayed(reset.Id)
+			if err != nil {
+				return err
+			}
+			s.markChanged(reset.Id)
+			return nil
+		}
+	}
+
+	moves := []struct {
+		nextMatchId *int
+		slotIsA     *bool
+	}{
+		{match.NextMatchId, match.NextMatchSlotIsA},
+		{match.LoserNextMatchId, match.LoserNextMatchSlotIsA},
+	}
+	for _, move := range moves {
+		if move.nextMatchId == nil || move.slotIsA == nil {
+			continue
+		}
+
+		next, err := s.repo.LockMatchById(*move.nextMatchId)
+		if err != nil {
+			return err
+		}
+		if next.Status != string(enums.Upcoming) {
+			return ErrNextMatchStarted
+		}
+		if err := s.repo.RemoveOpponentFromMatch(next, *move.slotIsA); err != nil {
+			return err
+		}
+		s.markChanged(next.Id)
+	}
+	return nil
+}
+
+// isGrandFinalWithReset reports whether the match is the grand final of a
+// double elimination, which is followed by its reset match.
+func isGrandFinalWithReset(match db.Match) bool {
+	return match.Bracket == string(enums.GrandFinalBracket) && match.NextMatchId != nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestSeedPositions(t *testing.T) {
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{2, []int{1, 2}},
+		{4, []int{1, 4, 2, 3}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{16, []int{1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11}},
+	}
+
+	for _, tt := range tests {
+		if got := seedPositions(tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("seedPositions(%d) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceBySeed(t *testing.T) {
+	tests := []struct {
+		entries int
+		want    []int
+	}{
+		{2, []int{1, 2}},
+		{3, []int{1, 0, 2, 3}},
+		{5, []int{1, 0, 4, 5, 2, 0, 3, 0}},
+		{8, []int{1, 8, 4, 5, 2, 7, 3, 6}},
+		{13, []int{1, 0, 8, 9, 4, 13, 5, 12, 2, 0, 7, 10, 3, 0, 6, 11}},
+	}
+
+	for _, tt := range tests {
+		if got := placeBySeed(seededEntries(tt.entries)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("placeBySeed(%d entries) = %v, want %v", tt.entries, got, tt.want)
+		}
+	}
+}
+
+func TestCreateBracket(t *testing.T) {
+	for _, entryCount := range []int{2, 3, 5, 8, 13} {
+		repo, svc := newBracketFixture()
+		draw := placeBySeed(seededEntries(entryCount))
+		if err := svc.createBracket(1, draw, enums.SingleElimination, bracketSettings()); err != nil {
+			t.Fatalf("%d entries: createBracket() error = %v", entryCount, err)
+		}
+
+		size := len(draw)
+		byes := size - entryCount
+		rounds := 0
+		for n := size; n > 1; n /= 2 {
+			rounds += 1
+		}
+
+		matchIds := make(map[int]db.Match)
+		for _, match := range repo.matches {
+			matchIds[match.Id] = match
+		}
+		if len(repo.matches) != entryCount-1 {
+			t.Errorf("%d entries: %d matches, want %d", entryCount, len(repo.matches), entryCount-1)
+		}
+
+		placed := make(map[int]db.Match)
+		for _, match := range repo.matches {
+			if *match.BracketRound == rounds {
+				if match.NextMatchId != nil {
+					t.Errorf("%d entries: final %d has a next match", entryCount, match.Id)
+				}
+			} else if _, ok := matchIds[*match.NextMatchId]; !ok {
+				t.Errorf("%d entries: match %d points at unknown match %d", entryCount, match.Id, *match.NextMatchId)
+			}
+			for _, opponentId := range repo.opponentsOf(match.Id) {
+				if opponentId == 0 {
+					continue
+				}
+				if _, ok := placed[opponentId]; ok {
+					t.Errorf("%d entries: seed %d placed twice", entryCount, opponentId)
+				}
+				placed[opponentId] = match
+			}
+		}
+		if len(placed) != entryCount {
+			t.Errorf("%d entries: %d seeds placed, want %d", entryCount, len(placed), entryCount)
+		}
+
+		for seed := 1; seed <= entryCount; seed++ {
+			wantRound := 1
+			if seed <= byes {
+				wantRound = 2
+			}
+			if got := *placed[seed].BracketRound; got != wantRound {
+				t.Errorf("%d entries: seed %d starts in round %d, want %d", entryCount, seed, got, wantRound)
+			}
+			if seed > byes && seed <= size/2 {
+				opponents := repo.opponentsOf(placed[seed].Id)
+				if want := [2]int{seed, size + 1 - seed}; opponents != want {
+					t.Errorf("%d entries: seed %d plays %v, want %v", entryCount, seed, opponents, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEndMatchReportsNextMatch(t *testing.T) {
+	repo, svc := newBracketFixture()
+	if err := svc.createBracket(1, placeBySeed(seededEntries(4)), enums.SingleElimination, bracketSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	var semiFinal db.Match
+	for _, match := range repo.matches {
+		if *match.BracketRound == 1 && *match.BracketPosition == 0 {
+			semiFinal = match
+		}
+	}
+
+	changed, err := svc.EndMatch(semiFinal.Id, enums.Walkover, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{*semiFinal.NextMatchId}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("EndMatch() changed = %v, want %v", changed, want)
+	}
+	if got := repo.opponentsOf(*semiFinal.NextMatchId); got != [2]int{4, 0} {
+		t.Errorf("final opponents = %v, want [4 0]", got)
+	}
+}
+
+func TestUndoBlockedByStartedNextMatchRollsBack(t *testing.T) {
+	repo, svc := newBracketFixture()
+	settings := bracketSettings()
+	settings.MaxSets = 1
+	if err := svc.createBracket(1, placeBySeed(seededEntries(4)), enums.SingleElimination, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	var semiFinals []db.Match
+	for _, match := range repo.matches {
+		if *match.BracketRound == 1 {
+			semiFinals = append(semiFinals, match)
+		}
+	}
+	for _, semiFinal := range semiFinals {
+		if err := svc.CreateSet(semiFinal.Id, nil); err != nil {
+			t.Fatal(err)
+		}
+		setId := repo.sets[len(repo.sets)-1].Id
+		for i := 0; i < 11; i++ {
+			if _, err := svc.UpdateScore(semiFinal.Id, setId, true, false, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	final := *semiFinals[0].NextMatchId
+	if err := svc.CreateSet(final, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	setLogCount := len(repo.setLogs)
+	if _, err := svc.Undo(semiFinals[0].Id); !errors.Is(err, ErrNextMatchStarted) {
+		t.Fatalf("Undo() error = %v, want %v", err, ErrNextMatchStarted)
+	}
+
+	if len(repo.setLogs) != setLogCount || len(repo.redos) != 0 {
+		t.Errorf("%d points and %d redos after the failed undo, want %d and none", len(repo.setLogs), len(repo.redos), setLogCount)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 11 || !set.IsCompleted {
+		t.Errorf("semi-final set is %d-%d completed %v, want 11-0 kept", set.OpponentAScore, set.OpponentBScore, set.IsCompleted)
+	}
+	if match := repo.findMatch(semiFinals[0].Id); match.Status != string(enums.Past) {
+		t.Errorf("semi-final is %s, want still completed", match.Status)
+	}
+}
+
+// seededEntries enters players 1 to count, each seeded by their id.
+func seededEntries(count int) []BracketEntry {
+	entries := make([]BracketEntry, 0, count)
+	for id := 1; id <= count; id++ {
+		entries = append(entries, BracketEntry{OpponentId: id, Seed: id})
+	}
+	return entries
+}
+
+func newBracketFixture() (*fakeRepository, *service) {
+	repo := newFakeRepository()
+	repo.tournamentEvents = []db.Event{{Id: 1, TournamentId: 1, Name: "Men's Singles", Format: string(enums.Singles)}}
+	return repo, &service{repo: repo}
+}
+
+func bracketSettings() MatchSettings {
+	return MatchSettings{MaxSets: 5, GamePoint: 11, ScoringRules: enums.StandardRules}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrInvalidMatchEvent = errors.New("invalid match event")
+var ErrOpponentRequired = errors.New("opponent required for match event")
+var ErrTimeoutAlreadyTaken = errors.New("timeout already taken")
+var ErrYellowCardAlreadyShown = errors.New("yellow card already shown")
+var ErrYellowCardRequired = errors.New("yellow card required before yellow-red card")
+var ErrNoSetInProgress = errors.New("no set in progress")
+
+type matchEvent struct {
+	Id        int
+	SetId     *int
+	Type      enums.MatchEventType
+	IsOppA    *bool
+	Detail    string
+	CreatedAt time.Time
+}
+
+// timelineEntry is either a point or a match event, in the order they were
+// recorded.
+type timelineEntry struct {
+	SetNumber int
+	Time      time.Time
+	Point     *setLog
+	Event     *matchEvent
+}
+
+// RecordMatchEvent adds an umpire event to the match. Each opponent may take
+// one timeout per match, and a yellow-red card can only follow the yellow card.
+func (s *service) RecordMatchEvent(
+	matchId int,
+	eventType enums.MatchEventType,
+	isOppA *bool,
+	detail string,
+) error {
+	return s.inTx(func(tx *service) error {
+		return tx.recordMatchEvent(matchId, eventType, isOppA, detail)
+	})
+}
+
+func (s *service) recordMatchEvent(
+	matchId int,
+	eventType enums.MatchEventType,
+	isOppA *bool,
+	detail string,
+) error {
+	match, err := s.repo.LockMatchById(matchId)
+	if err != nil {
+		return err
+	}
+	if match.Status == string(enums.Past) {
+		return ErrMatchAlreadyCompleted
+	}
+
+	events, err := s.repo.GetMatchEventsByMatchId(matchId)
+	if err != nil {
+		return err
+	}
+	sets, err := s.repo.GetSetsByMatchId(matchId)
+	if err != nil {
+		return err
+	}
+
+	var setId *int
+	if len(sets) > 0 && !sets[len(sets)-1].IsCompleted {
+		setId = &sets[len(sets)-1].Id
+	}
+
+	if err := validateMatchEvent(eventType, isOppA, setId, events); err != nil {
+		return err
+	}
+
+	return s.repo.CreateMatchEvent(&db.MatchEvent{
+		MatchId:     matchId,
+		SetId:       setId,
+		Type:        string(eventType),
+		IsOpponentA: isOppA,
+		Detail:      detail,
+	})
+}
+
+func validateMatchEvent(eventType enums.MatchEventType, isOppA *bool, setId *int, events []db.MatchEvent) error {
+	switch eventType {
+	case enums.Let:
+		if setId == nil {
+			return ErrNoSetInProgress
+		}
+		return nil
+	case enums.Timeout, enums.YellowCard, enums.YellowRedCard, enums.RedCard, enums.MedicalBreak:
+		if isOppA == nil {
+			return ErrOpponentRequired
+		}
+	default:
+		return ErrInvalidMatchEvent
+	}
+
+	counts := countEventsForOpponent(events, *isOppA)
+	switch eventType {
+	case enums.Timeout:
+		if counts[enums.Timeout] > 0 {
+			return ErrTimeoutAlreadyTaken
+		}
+	case enums.YellowCard:
+		if counts[enums.YellowCard] > 0 {
+			return ErrYellowCardAlreadyShown
+		}
+	case enums.YellowRedCard:
+		if counts[enums.YellowCard] == 0 {
+			return ErrYellowCardRequired
+		}
+	}
+	return nil
+}
+
+func countEventsForOpponent(events []db.MatchEvent, isOppA bool) map[enums.MatchEventType]int {
+	counts := make(map[enums.MatchEventType]int)
+	for _, event := range events {
+		if event.IsOpponentA != nil && *event.IsOpponentA == isOppA {
+			counts[enums.MatchEventType(event.Type)] += 1
+		}
+	}
+	return counts
+}
+
+func newMatchEvent(event db.MatchEvent) matchEvent {
+	return matchEvent{
+		Id:        event.Id,
+		SetId:     event.SetId,
+		Type:      enums.MatchEventType(event.Type),
+		IsOppA:    event.IsOpponentA,
+		Detail:    event.Detail,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// buildTimeline interleaves points and events by the time they were recorded.
+// Events recorded between sets are placed with the set they followed.
+func buildTimeline(sets []set, events []matchEvent) []timelineEntry {
+	setNumbers := make(map[int]int)
+	timeline := make([]timelineEntry, 0)
+	for _, s := range sets {
+		setNumbers[s.Id] = s.SetNumber
+		for i := range s.Logs {
+			timeline = append(timeline, timelineEntry{
+				SetNumber: s.SetNumber,
+				Time:      s.Logs[i].CreatedAt,
+				Point:     &s.Logs[i],
+			})
+		}
+	}
+
+	for i := range events {
+		setNumber := 0
+		if events[i].SetId != nil {
+			setNumber = setNumbers[*events[i].SetId]
+		} else {
+			for _, s := range sets {
+				if !s.StartedAt.After(events[i].CreatedAt) {
+					setNumber = s.SetNumber
+				}
+			}
+		}
+		timeline = append(timeline, timelineEntry{
+			SetNumber: setNumber,
+			Time:      events[i].CreatedAt,
+			Event:     &events[i],
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		if timeline[i].SetNumber != timeline[j].SetNumber {
+			return timeline[i].SetNumber < timeline[j].SetNumber
+		}
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+
+	return timeline
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestValidateMatchEvent(t *testing.T) {
+	oppA, oppB := true, false
+	setId := 1
+	yellowForA := db.MatchEvent{Type: string(enums.YellowCard), IsOpponentA: &oppA}
+	timeoutForA := db.MatchEvent{Type: string(enums.Timeout), IsOpponentA: &oppA}
+
+	tests := []struct {
+		name      string
+		eventType enums.MatchEventType
+		isOppA    *bool
+		setId     *int
+		events    []db.MatchEvent
+		want      error
+	}{
+		{"let during a set", enums.Let, nil, &setId, nil, nil},
+		{"let between sets", enums.Let, nil, nil, nil, ErrNoSetInProgress},
+		{"timeout without opponent", enums.Timeout, nil, &setId, nil, ErrOpponentRequired},
+		{"first timeout", enums.Timeout, &oppA, &setId, nil, nil},
+		{"second timeout", enums.Timeout, &oppA, &setId, []db.MatchEvent{timeoutForA}, ErrTimeoutAlreadyTaken},
+		{"other opponent's timeout", enums.Timeout, &oppB, &setId, []db.MatchEvent{timeoutForA}, nil},
+		{"second yellow card", enums.YellowCard, &oppA, &setId, []db.MatchEvent{yellowForA}, ErrYellowCardAlreadyShown},
+		{"yellow-red without yellow", enums.YellowRedCard, &oppB, &setId, []db.MatchEvent{yellowForA}, ErrYellowCardRequired},
+		{"yellow-red after yellow", enums.YellowRedCard, &oppA, &setId, []db.MatchEvent{yellowForA}, nil},
+		{"change of ends from the umpire", enums.ChangeEnds, &oppA, &setId, nil, ErrInvalidMatchEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMatchEvent(tt.eventType, tt.isOppA, tt.setId, tt.events); !errors.Is(err, tt.want) {
+				t.Errorf("validateMatchEvent() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	start := time.Date(2023, 11, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	firstSetId, secondSetId := 1, 2
+	sets := []set{
+		{Id: firstSetId, SetNumber: 1, StartedAt: at(0), Logs: []setLog{{Id: 10, CreatedAt: at(1)}, {Id: 11, CreatedAt: at(3)}}},
+		{Id: secondSetId, SetNumber: 2, StartedAt: at(6), Logs: []setLog{{Id: 12, CreatedAt: at(7)}}},
+	}
+	events := []matchEvent{
+		{Id: 20, SetId: &firstSetId, Type: enums.Let, CreatedAt: at(2)},
+		{Id: 21, Type: enums.MedicalBreak, CreatedAt: at(5)},
+		{Id: 22, SetId: &firstSetId, Type: enums.ChangeEnds, CreatedAt: at(4)},
+		{Id: 23, SetId: &secondSetId, Type: enums.Timeout, CreatedAt: at(8)},
+	}
+
+	timeline := buildTimeline(sets, events)
+
+	want := []struct {
+		setNumber int
+		id        int
+		isPoint   bool
+	}{
+		{1, 10, true},
+		{1, 20, false},
+		{1, 11, true},
+		{1, 22, false},
+		{1, 21, false},
+		{2, 12, true},
+		{2, 23, false},
+	}
+	if len(timeline) != len(want) {
+		t.Fatalf("%d timeline entries, want %d", len(timeline), len(want))
+	}
+	for i, w := range want {
+		entry := timeline[i]
+		id := 0
+		if entry.Point != nil {
+			id = entry.Point.Id
+		} else {
+			id = entry.Event.Id
+		}
+		if entry.SetNumber != w.setNumber || id != w.id || (entry.Point != nil) != w.isPoint {
+			t.Errorf("entry %d is %d in set %d, want %d in set %d", i, id, entry.SetNumber, w.id, w.setNumber)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrInvalidSetCount = errors.New("set count must be a positive odd number")
+var ErrInvalidGamePoint = errors.New("game point must be positive")
+var ErrMatchFormatTemplateNotFound = errors.New("match format template not found")
+var ErrMatchFormatTemplateConflict = errors.New("max sets, game point and scoring rules must match the format template when given")
+var ErrMatchFormatTemplateExists = errors.New("match format template already exists")
+var ErrMatchFormatTemplateNameRequired = errors.New("match format template name required")
+
+type matchFormatTemplate struct {
+	Name         string
+	SetCount     int
+	GamePoint    int
+	ScoringRules enums.ScoringRuleSet
+}
+
+func (s *service) CreateMatchFormatTemplate(
+	name string,
+	setCount int,
+	gamePoint int,
+	scoringRules enums.ScoringRuleSet,
+) error {
+	if name == "" {
+		return ErrMatchFormatTemplateNameRequired
+	}
+	if err := validateMatchFormat(setCount, gamePoint, scoringRules); err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *service) error {
+		_, err := tx.repo.GetMatchFormatTemplateByName(name)
+		if err == nil {
+			return ErrMatchFormatTemplateExists
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		return tx.repo.CreateMatchFormatTemplate(&db.MatchFormatTemplate{
+			Name:         name,
+			SetCount:     setCount,
+			GamePoint:    gamePoint,
+			ScoringRules: string(scoringRules),
+		})
+	})
+}
+
+func (s *service) GetMatchFormatTemplates() ([]matchFormatTemplate, error) {
+	templatesFromDb, err := s.repo.GetAllMatchFormatTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]matchFormatTemplate, 0, len(templatesFromDb))
+	for _, t := range templatesFromDb {
+		templates = append(templates, newMatchFormatTemplate(t))
+	}
+	return templates, nil
+}
+
+func (s *service) GetMatchFormatTemplate(name string) (*matchFormatTemplate, error) {
+	template, err := s.getMatchFormatTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newMatchFormatTemplate(*template)
+	return &t, nil
+}
+
+func (s *service) UpdateMatchFormatTemplate(
+	name string,
+	setCount int,
+	gamePoint int,
+	scoringRules enums.ScoringRuleSet,
+) error {
+	if err := validateMatchFormat(setCount, gamePoint, scoringRules); err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *service) error {
+		template, err := tx.getMatchFormatTemplate(name)
+		if err != nil {
+			return err
+		}
+
+		template.SetCount = setCount
+		template.GamePoint = gamePoint
+		template.ScoringRules = string(scoringRules)
+		return tx.repo.UpdateMatchFormatTemplate(template)
+	})
+}
+
+func (s *service) DeleteMatchFormatTemplate(name string) error {
+	return s.inTx(func(tx *service) error {
+		if _, err := tx.getMatchFormatTemplate(name); err != nil {
+			return err
+		}
+		return tx.repo.DeleteMatchFormatTemplate(name)
+	})
+}
+
+func (s *service) getMatchFormatTemplate(name string) (*db.MatchFormatTemplate, error) {
+	template, err := s.repo.GetMatchFormatTemplateByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMatchFormatTemplateNotFound
+	}
+	return template, err
+}
+
+// applyMatchFormatTemplate replaces the set count, game point and scoring
+// rules of the settings with those of the named template. A set count, game
+// point or scoring rules given alongside the template must agree with it.
+func (s *service) applyMatchFormatTemplate(settings *MatchSettings) error {
+	template, err := s.getMatchFormatTemplate(settings.FormatTemplate)
+	if err != nil {
+		return err
+	}
+	if (settings.MaxSets != 0 && settings.MaxSets != template.SetCount) ||
+		(settings.GamePoint != 0 && settings.GamePoint != template.GamePoint) ||
+		(settings.ScoringRules != "" && string(settings.ScoringRules) != template.ScoringRules) {
+		return ErrMatchFormatTemplateConflict
+	}
+
+	settings.MaxSets = template.SetCount
+	settings.GamePoint = template.GamePoint
+	settings.ScoringRules = enums.ScoringRuleSet(template.ScoringRules)
+	return nil
+}
+
+// validateMatchFormat rejects formats that cannot produce a winner. An odd
+// set count is needed for one opponent to win a majority of the sets.
+func validateMatchFormat(setCount int, gamePoint int, scoringRules enums.ScoringRuleSet) error {
+	if setCount <= 0 || setCount%2 == 0 {
+		return ErrInvalidSetCount
+	}
+	if gamePoint <= 0 {
+		return ErrInvalidGamePoint
+	}
+	_, err := ScoringRulesFor(scoringRules)
+	return err
+}
+
+func newMatchFormatTemplate(template db.MatchFormatTemplate) matchFormatTemplate {
+	return matchFormatTemplate{
+		Name:         template.Name,
+		SetCount:     template.SetCount,
+		GamePoint:    template.GamePoint,
+		ScoringRules: enums.ScoringRuleSet(template.ScoringRules),
+	}
+}
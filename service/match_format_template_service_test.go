@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestNewMatchAppliesFormatTemplate(t *testing.T) {
+	repo := newFakeRepository()
+	repo.formatTemplates = []db.MatchFormatTemplate{
+		{Name: "golden", SetCount: 3, GamePoint: 11, ScoringRules: string(enums.GoldenPointRules)},
+	}
+	svc := &service{repo: repo}
+
+	tests := []struct {
+		name     string
+		settings MatchSettings
+		want     error
+	}{
+		{"template only", MatchSettings{FormatTemplate: "golden"}, nil},
+		{"agreeing settings", MatchSettings{FormatTemplate: "golden", MaxSets: 3, GamePoint: 11, ScoringRules: enums.GoldenPointRules}, nil},
+		{"conflicting max sets", MatchSettings{FormatTemplate: "golden", MaxSets: 5}, ErrMatchFormatTemplateConflict},
+		{"conflicting game point", MatchSettings{FormatTemplate: "golden", GamePoint: 21}, ErrMatchFormatTemplateConflict},
+		{"conflicting scoring rules", MatchSettings{FormatTemplate: "golden", ScoringRules: enums.StandardRules}, ErrMatchFormatTemplateConflict},
+		{"unknown template", MatchSettings{FormatTemplate: "short"}, ErrMatchFormatTemplateNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := svc.newMatch(enums.Singles, enums.Prelims, tt.settings)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("newMatch() error = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if match.SetCount != 3 || match.GamePoint != 11 || match.ScoringRules != string(enums.GoldenPointRules) {
+				t.Errorf("match is best of %d to %d with %s, want the template", match.SetCount, match.GamePoint, match.ScoringRules)
+			}
+		})
+	}
+}
+
+func TestNewMatchDefaultsToStandardRules(t *testing.T) {
+	svc := &service{repo: newFakeRepository()}
+
+	match, err := svc.newMatch(enums.Singles, enums.Prelims, MatchSettings{MaxSets: 5, GamePoint: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.ScoringRules != string(enums.StandardRules) {
+		t.Errorf("scoring rules = %s, want %s", match.ScoringRules, enums.StandardRules)
+	}
+}
@@ -1,52 +1,87 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/adarsh-a-tw/tt-backend/db"
 	"github.com/adarsh-a-tw/tt-backend/enums"
 )
 
+var ErrInvalidMatchResult = errors.New("invalid match result")
+var ErrMatchAlreadyCompleted = errors.New("match already completed")
+var ErrMatchAlreadyStarted = errors.New("match already started")
+var ErrInvalidHandicap = errors.New("handicap must be less than the game point")
+
 type opponent struct {
 	Id       int
 	Name     string
 	IsWinner bool
+	Players  []Player
 }
 
 type matchInfo struct {
-	Id        int
-	Format    enums.MatchFormat
-	Stage     enums.MatchStage
-	Status    enums.MatchStatus
-	Opponents []opponent
+	Id           int
+	EventId      *int
+	TieId        *int
+	RubberNumber *int
+	SeasonId     *int
+	MatchWeek    *int
+	Deadline     *time.Time
+	Format       enums.MatchFormat
+	Stage        enums.MatchStage
+	Status       enums.MatchStatus
+	Result       enums.MatchResult
+	Opponents    []opponent
 }
 
-func (s *service) GetMatchInfoList(status string) ([]matchInfo, error) {
+// GetMatchInfoList lists the matches with the given status in the given
+// tournament and event. Empty or nil filters are not applied.
+func (s *service) GetMatchInfoList(status string, tournamentId *int, eventId *int) ([]matchInfo, error) {
 	matches := []db.Match{}
-	err := s.repo.GetAllMatches(&matches, status)
+	err := s.repo.GetAllMatches(&matches, status, tournamentId, eventId)
 	if err != nil {
 		return nil, err
 	}
 
 	matchInfoList := make([]matchInfo, 0)
 	for _, match := range matches {
-		opponents, err := s.opponentsFromMatch(match)
+		info, err := s.newMatchInfo(match)
 		if err != nil {
 			return nil, err
 		}
-
-		matchInfoList = append(matchInfoList, matchInfo{
-			Id:        match.Id,
-			Format:    enums.MatchFormat(match.Format),
-			Stage:     enums.MatchStage(match.Stage),
-			Status:    enums.MatchStatus(match.Status),
-			Opponents: opponents,
-		})
+		matchInfoList = append(matchInfoList, *info)
 	}
 
 	return matchInfoList, nil
 }
 
+func (s *service) newMatchInfo(match db.Match) (*matchInfo, error) {
+	opponents, err := s.opponentsFromMatch(match)
+	if err != nil {
+		return nil, err
+	}
+
+	return &matchInfo{
+		Id:           match.Id,
+		EventId:      match.EventId,
+		TieId:        match.TieId,
+		RubberNumber: match.RubberNumber,
+		SeasonId:     match.SeasonId,
+		MatchWeek:    match.MatchWeek,
+		Deadline:     match.Deadline,
+		Format:       enums.MatchFormat(match.Format),
+		Stage:        enums.MatchStage(match.Stage),
+		Status:       enums.MatchStatus(match.Status),
+		Result:       enums.MatchResult(match.Result),
+		Opponents:    opponents,
+	}, nil
+}
+
+// opponentsFromMatch returns opponent A followed by opponent B. A bracket
+// match whose opponents are not decided yet has an empty opponent in their
+// place.
 func (s *service) opponentsFromMatch(match db.Match) ([]opponent, error) {
 	opponents := make([]opponent, 2)
 	if match.Format == string(enums.Doubles) {
@@ -54,95 +89,334 @@ func (s *service) opponentsFromMatch(match db.Match) ([]opponent, error) {
 		if err != nil {
 			return nil, err
 		}
-		for i, row := range rows {
-			opponents[i] = opponent{row.TeamId, fmt.Sprintf("%s & %s", row.PlayerA, row.PlayerB), row.IsWinner}
+		for _, row := range rows {
+			players := make([]Player, 0, 2)
+			for _, player := range row.Players() {
+				players = append(players, newPlayer(player))
+			}
+			opponents[opponentIndex(row.IsOpponentA)] = opponent{
+				Id:       row.TeamId,
+				Name:     fmt.Sprintf("%s & %s", row.PlayerA, row.PlayerB),
+				IsWinner: row.IsWinner,
+				Players:  players,
+			}
 		}
 	} else {
 		rows, err := s.repo.GetPlayerInfoByMatchId(match.Id)
 		if err != nil {
 			return nil, err
 		}
-		for i, row := range rows {
-			opponents[i] = opponent{row.PlayerId, row.PlayerName, row.IsWinner}
+		for _, row := range rows {
+			player := newPlayer(db.Player{
+				Id:           row.PlayerId,
+				Name:         row.PlayerName,
+				TournamentId: row.TournamentId,
+				Club:         row.Club,
+				Country:      row.Country,
+				Handedness:   row.Handedness,
+				GripStyle:    row.GripStyle,
+				BirthYear:    row.BirthYear,
+				Gender:       row.Gender,
+				PhotoUrl:     row.PhotoUrl,
+			})
+			opponents[opponentIndex(row.IsOpponentA)] = opponent{
+				Id:       row.PlayerId,
+				Name:     row.PlayerName,
+				IsWinner: row.IsWinner,
+				Players:  []Player{player},
+			}
 		}
 	}
 	return opponents, nil
 }
 
+func opponentIndex(isOppA bool) int {
+	if isOppA {
+		return 0
+	}
+	return 1
+}
+
+// opponentIdsFromMatch maps whether an opponent is opponent A to its player or
+// team id. Opponents that are not decided yet are left out.
+func (s *service) opponentIdsFromMatch(match db.Match) (map[bool]int, error) {
+	ids := make(map[bool]int)
+	if match.Format == string(enums.Doubles) {
+		rows, err := s.repo.GetTeamInfoByMatchId(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			ids[row.IsOpponentA] = row.TeamId
+		}
+	} else {
+		rows, err := s.repo.GetPlayerInfoByMatchId(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			ids[row.IsOpponentA] = row.PlayerId
+		}
+	}
+	return ids, nil
+}
+
+// addOpponentToMatch adds the player or team to the match, depending on its
+// format.
+func (s *service) addOpponentToMatch(match db.Match, opponentId int, isOppA bool) error {
+	if match.Format == string(enums.Doubles) {
+		return s.repo.AddTeamToMatch(
+			&db.TeamMatchMapping{MatchId: match.Id, TeamId: opponentId, IsOpponentA: isOppA},
+		)
+	}
+	return s.repo.AddPlayerToMatch(
+		&db.PlayerMatchMapping{MatchId: match.Id, PlayerId: opponentId, IsOpponentA: isOppA},
+	)
+}
+
+// MatchSettings describes how a match is played. When FormatTemplate is set
+// the set count, game point and scoring rules are taken from the named
+// template, otherwise unset scoring rules are the standard rules. HandicapA
+// and HandicapB are the points each opponent starts every set with. EventId
+// places the match in an event of a tournament.
+type MatchSettings struct {
+	EventId        *int
+	FormatTemplate string
+	MaxSets        int
+	GamePoint      int
+	ScoringRules   enums.ScoringRuleSet
+	HandicapA      int
+	HandicapB      int
+}
+
 func (s *service) CreateSinglesMatch(
 	stage enums.MatchStage,
 	playerAId int,
 	playerBId int,
-	maxSets int,
-	gamePoint int,
+	settings MatchSettings,
 ) error {
-	id, err := s.createMatch(enums.Singles, stage, maxSets, gamePoint)
-	if err != nil {
-		return err
-	}
-
-	err = s.repo.AddPlayerToMatch(
-		&db.PlayerMatchMapping{MatchId: int(id), PlayerId: playerAId, IsOpponentA: true},
-	)
-	if err != nil {
-		return err
-	}
+	return s.inTx(func(tx *service) error {
+		id, err := tx.createMatch(enums.Singles, stage, settings)
+		if err != nil {
+			return err
+		}
 
-	err = s.repo.AddPlayerToMatch(
-		&db.PlayerMatchMapping{MatchId: int(id), PlayerId: playerBId, IsOpponentA: false},
-	)
-	if err != nil {
-		return err
-	}
+		err = tx.repo.AddPlayerToMatch(
+			&db.PlayerMatchMapping{MatchId: int(id), PlayerId: playerAId, IsOpponentA: true},
+		)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		err = tx.repo.AddPlayerToMatch(
+			&db.PlayerMatchMapping{MatchId: int(id), PlayerId: playerBId, IsOpponentA: false},
+		)
+		if err != nil {
+			return err
+		}
 
+		return tx.checkOpponentsInEvent(int(id), enums.Singles, settings.EventId)
+	})
 }
 
 func (s *service) CreateDoublesMatch(
 	stage enums.MatchStage,
 	teamAId int,
 	teamBId int,
-	maxSets int,
-	gamePoint int,
+	settings MatchSettings,
 ) error {
-	id, err := s.createMatch(enums.Doubles, stage, maxSets, gamePoint)
+	return s.inTx(func(tx *service) error {
+		id, err := tx.createMatch(enums.Doubles, stage, settings)
+		if err != nil {
+			return err
+		}
+
+		err = tx.repo.AddTeamToMatch(
+			&db.TeamMatchMapping{MatchId: int(id), TeamId: teamAId, IsOpponentA: true},
+		)
+		if err != nil {
+			return err
+		}
+
+		err = tx.repo.AddTeamToMatch(
+			&db.TeamMatchMapping{MatchId: int(id), TeamId: teamBId, IsOpponentA: false},
+		)
+		if err != nil {
+			return err
+		}
+
+		return tx.checkOpponentsInEvent(int(id), enums.Doubles, settings.EventId)
+	})
+}
+
+func (s *service) createMatch(
+	format enums.MatchFormat,
+	stage enums.MatchStage,
+	settings MatchSettings,
+) (int64, error) {
+	match, err := s.newMatch(format, stage, settings)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return s.repo.CreateMatch(match)
+}
 
-	err = s.repo.AddTeamToMatch(
-		&db.TeamMatchMapping{MatchId: int(id), TeamId: teamAId, IsOpponentA: true},
-	)
+// newMatch validates the settings and builds an upcoming match from them.
+func (s *service) newMatch(
+	format enums.MatchFormat,
+	stage enums.MatchStage,
+	settings MatchSettings,
+) (*db.Match, error) {
+	if format == enums.Team {
+		return nil, ErrTeamEventNeedsTies
+	}
+	if settings.EventId != nil {
+		event, err := s.getEvent(*settings.EventId)
+		if err != nil {
+			return nil, err
+		}
+		if event.Format != string(format) && event.Format != string(enums.Team) {
+			return nil, ErrEventFormatMismatch
+		}
+	}
+	if settings.FormatTemplate != "" {
+		if err := s.applyMatchFormatTemplate(&settings); err != nil {
+			return nil, err
+		}
+	}
+	if settings.ScoringRules == "" {
+		settings.ScoringRules = enums.StandardRules
+	}
+	if err := validateMatchFormat(settings.MaxSets, settings.GamePoint, settings.ScoringRules); err != nil {
+		return nil, err
+	}
+	if settings.HandicapA < 0 || settings.HandicapA >= settings.GamePoint ||
+		settings.HandicapB < 0 || settings.HandicapB >= settings.GamePoint {
+		return nil, ErrInvalidHandicap
+	}
+
+	return &db.Match{
+		Format:         string(format),
+		Stage:          string(stage),
+		SetCount:       settings.MaxSets,
+		GamePoint:      settings.GamePoint,
+		Status:         string(enums.Upcoming),
+		FirstServerIsA: true,
+		ScoringRules:   string(settings.ScoringRules),
+		HandicapA:      settings.HandicapA,
+		HandicapB:      settings.HandicapB,
+		FormatTemplate: settings.FormatTemplate,
+		EventId:        settings.EventId,
+	}, nil
+}
+
+// EndMatch finishes a match without counting won sets. A walkover can only be
+// given before the match has started.
+func (s *service) EndMatch(matchId int, result enums.MatchResult, winnerIsA bool) ([]int, error) {
+	if result != enums.Walkover && result != enums.Retired && result != enums.Disqualified {
+		return nil, ErrInvalidMatchResult
+	}
+
+	return s.inTxChanging(func(tx *service) error {
+		match, err := tx.repo.LockMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		if match.Status == string(enums.Past) {
+			return ErrMatchAlreadyCompleted
+		}
+		if result == enums.Walkover && match.Status != string(enums.Upcoming) {
+			return ErrMatchAlreadyStarted
+		}
+		opponentIds, err := tx.opponentIdsFromMatch(*match)
+		if err != nil {
+			return err
+		}
+		if len(opponentIds) < 2 {
+			return ErrOpponentsNotDecided
+		}
+
+		return tx.completeMatch(match, winnerIsA, result)
+	})
+}
+
+func (s *service) completeMatch(match *db.Match, winnerIsA bool, result enums.MatchResult) error {
+	err := s.repo.UpdateMatchWinner(match, winnerIsA)
 	if err != nil {
 		return err
 	}
-
-	err = s.repo.AddTeamToMatch(
-		&db.TeamMatchMapping{MatchId: int(id), TeamId: teamBId, IsOpponentA: false},
-	)
+	err = s.repo.UpdateMatchResult(match.Id, string(result))
+	if err != nil {
+		return err
+	}
+	err = s.repo.UpdateMatchStatus(match.Id, string(enums.Past))
+	if err != nil {
+		return err
+	}
+	err = s.rateMatch(match, winnerIsA, result)
+	if err != nil {
+		return err
+	}
+	err = s.advanceOpponents(match, winnerIsA)
 	if err != nil {
 		return err
 	}
+	return s.refreshTie(match)
+}
 
-	return nil
+// reopenMatch reverts a completed match to ongoing and clears its winner.
+// Opponents that moved on in a bracket are taken out of their next matches
+// again, which is only possible while those matches have not started, and
+// the rating changes of the match are rolled back.
+func (s *service) reopenMatch(match *db.Match) error {
+	err := s.withdrawOpponents(match)
+	if err != nil {
+		return err
+	}
+	err = s.unrateMatch(match)
+	if err != nil {
+		return err
+	}
+	err = s.repo.UpdateMatchStatus(match.Id, string(enums.Ongoing))
+	if err != nil {
+		return err
+	}
+	err = s.repo.UpdateMatchResult(match.Id, "")
+	if err != nil {
+		return err
+	}
+	err = s.repo.ResetMatchWinner(match)
+	if err != nil {
+		return err
+	}
+	return s.refreshTie(match)
+}
 
+// closeUnplayed closes a match that is no longer needed, such as a dead
+// rubber of a decided tie, as unplayed.
+func (s *service) closeUnplayed(matchId int) error {
+	err := s.repo.UpdateMatchResult(matchId, string(enums.Unplayed))
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateMatchStatus(matchId, string(enums.Past))
 }
 
-func (s *service) createMatch(
-	format enums.MatchFormat,
-	stage enums.MatchStage,
-	maxSets int,
-	gamePoint int,
-) (int64, error) {
-	match := &db.Match{
-		Format:    string(format),
-		Stage:     string(stage),
-		SetCount:  maxSets,
-		GamePoint: gamePoint,
-		Status:    string(enums.Upcoming),
+// reopenUnplayed opens a match closed as unplayed again, as ongoing if it
+// was closed after it started.
+func (s *service) reopenUnplayed(matchId int) error {
+	err := s.repo.UpdateMatchResult(matchId, "")
+	if err != nil {
+		return err
 	}
-	return s.repo.CreateMatch(match)
+	sets, err := s.repo.GetSetsByMatchId(matchId)
+	if err != nil {
+		return err
+	}
+	if len(sets) > 0 {
+		return s.repo.UpdateMatchStatus(matchId, string(enums.Ongoing))
+	}
+	return s.repo.UpdateMatchStatus(matchId, string(enums.Upcoming))
 }
 
 type set struct {
@@ -151,6 +425,10 @@ type set struct {
 	OpponentAScore int
 	OpponentBScore int
 	IsCompleted    bool
+	Version        int
+	FirstServerIsA bool
+	IsExpedited    bool
+	StartedAt      time.Time
 	Logs           []setLog
 }
 
@@ -159,15 +437,36 @@ type setLog struct {
 	OppAScore int
 	OppBScore int
 	ScoredByA bool
+	ServedByA bool
+	CreatedAt time.Time
 }
 
 type MatchDetail struct {
-	Id        int
-	Format    string
-	Stage     string
-	Status    string
-	Opponents []opponent
-	Sets      []set
+	Id             int
+	TournamentId   *int
+	EventId        *int
+	GroupId        *int
+	Tie            *tieSummary
+	RubberNumber   *int
+	Bracket        string
+	Format         string
+	Stage          string
+	Status         string
+	Result         string
+	FormatTemplate string
+	SetCount       int
+	GamePoint      int
+	ScoringRules   string
+	HandicapA      int
+	HandicapB      int
+	Opponents      []opponent
+	Sets           []set
+	ServerIsA      *bool
+	ServerName     string
+	ReceiverName   string
+	IsExpedited    bool
+	Events         []matchEvent
+	Timeline       []timelineEntry
 }
 
 func (svc *service) GetMatchDetails(matchId int) (*MatchDetail, error) {
@@ -181,11 +480,30 @@ func (svc *service) GetMatchDetails(matchId int) (*MatchDetail, error) {
 		return nil, err
 	}
 
+	tournamentId, err := svc.tournamentIdOfMatch(*match)
+	if err != nil {
+		return nil, err
+	}
+
+	tie, err := svc.tieOfMatch(*match)
+	if err != nil {
+		return nil, err
+	}
+
 	setsFromDb, err := svc.repo.GetSetsByMatchId(matchId)
 	if err != nil {
 		return nil, err
 	}
+	if match.Status != string(enums.Past) {
+		for i := range setsFromDb {
+			if !setsFromDb[i].IsCompleted {
+				startExpediteIfOverdue(*match, &setsFromDb[i], time.Now())
+			}
+		}
+	}
 
+	var currentServerIsA *bool
+	var serverName, receiverName string
 	sets := make([]set, 0)
 	for _, s := range setsFromDb {
 		setLogsFromDb, err := svc.repo.GetSetLogsBySetId(s.Id, nil)
@@ -200,25 +518,122 @@ func (svc *service) GetMatchDetails(matchId int) (*MatchDetail, error) {
 				OppAScore: sl.OppAScore,
 				OppBScore: sl.OppBScore,
 				ScoredByA: sl.ScoredByA,
+				ServedByA: sl.ServedByA,
+				CreatedAt: sl.CreatedAt,
 			})
 		}
 
+		firstServerIsA := setFirstServerIsA(*match, s.SetNumber)
+		if !s.IsCompleted && match.Status != string(enums.Past) {
+			serverIsA := nextServerIsA(*match, s)
+			currentServerIsA = &serverIsA
+			serverName, receiverName, err = svc.serverAndReceiverNames(*match, s, setLogsFromDb, opponents)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		sets = append(sets, set{
 			Id:             s.Id,
 			SetNumber:      s.SetNumber,
 			OpponentAScore: s.OpponentAScore,
 			OpponentBScore: s.OpponentBScore,
 			IsCompleted:    s.IsCompleted,
+			Version:        s.Version,
+			FirstServerIsA: firstServerIsA,
+			IsExpedited:    s.ExpediteFromPoint != nil,
+			StartedAt:      s.StartedAt,
 			Logs:           setLogs,
 		})
 	}
 
+	eventsFromDb, err := svc.repo.GetMatchEventsByMatchId(matchId)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]matchEvent, 0)
+	for _, e := range eventsFromDb {
+		events = append(events, newMatchEvent(e))
+	}
+
 	return &MatchDetail{
-		Id:        matchId,
-		Format:    match.Format,
-		Stage:     match.Stage,
-		Status:    match.Status,
-		Opponents: opponents,
-		Sets:      sets,
+		Id:             matchId,
+		TournamentId:   tournamentId,
+		EventId:        match.EventId,
+		GroupId:        match.GroupId,
+		Tie:            tie,
+		RubberNumber:   match.RubberNumber,
+		Bracket:        match.Bracket,
+		Format:         match.Format,
+		Stage:          match.Stage,
+		Status:         match.Status,
+		Result:         match.Result,
+		FormatTemplate: match.FormatTemplate,
+		SetCount:       match.SetCount,
+		GamePoint:      match.GamePoint,
+		ScoringRules:   match.ScoringRules,
+		HandicapA:      match.HandicapA,
+		HandicapB:      match.HandicapB,
+		Opponents:      opponents,
+		Sets:           sets,
+		ServerIsA:      currentServerIsA,
+		ServerName:     serverName,
+		ReceiverName:   receiverName,
+		IsExpedited:    isExpedited(setsFromDb),
+		Events:         events,
+		Timeline:       buildTimeline(sets, events),
 	}, nil
 }
+
+// serverAndReceiverNames returns the names of the players serving and
+// receiving the next point. In doubles they stay empty until the service
+// order of the set has been recorded. setLogs must be ordered newest first.
+func (svc *service) serverAndReceiverNames(
+	match db.Match,
+	s db.Set,
+	setLogs []db.SetLog,
+	opponents []opponent,
+) (string, string, error) {
+	serverIsA := nextServerIsA(match, s)
+	if match.Format != string(enums.Doubles) {
+		if serverIsA {
+			return opponents[0].Name, opponents[1].Name, nil
+		}
+		return opponents[1].Name, opponents[0].Name, nil
+	}
+
+	logs := make([]db.SetLog, len(setLogs))
+	for i, sl := range setLogs {
+		logs[len(setLogs)-1-i] = sl
+	}
+	serverIsPlayerA, receiverIsPlayerA, ok := doublesServiceOrder(match, s, logs)
+	if !ok {
+		return "", "", nil
+	}
+
+	rows, err := svc.repo.GetTeamInfoByMatchId(match.Id)
+	if err != nil {
+		return "", "", err
+	}
+	var serving, receiving *db.TeamInfoByMatchIdRow
+	for i := range rows {
+		if rows[i].IsOpponentA == serverIsA {
+			serving = &rows[i]
+		} else {
+			receiving = &rows[i]
+		}
+	}
+	if serving == nil || receiving == nil {
+		return "", "", nil
+	}
+
+	return teamPlayerName(*serving, serverIsPlayerA), teamPlayerName(*receiving, receiverIsPlayerA), nil
+}
+
+func teamPlayerName(row db.TeamInfoByMatchIdRow, isPlayerA bool) string {
+	if isPlayerA {
+		return row.PlayerA
+	}
+	return row.PlayerB
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestOpponentsFromDoublesMatchUseTheMatchRows(t *testing.T) {
+	match := db.Match{Id: 1, Format: string(enums.Doubles)}
+	repo := newFakeRepository(match)
+	repo.teamRows = []db.TeamInfoByMatchIdRow{
+		{MatchId: 1, TeamId: 7, IsOpponentA: false, PlayerAId: 3, PlayerA: "Cai", PlayerBId: 4, PlayerB: "Dev", PlayerBClub: "Riverside"},
+		{MatchId: 1, TeamId: 5, IsOpponentA: true, IsWinner: true, PlayerAId: 1, PlayerA: "Ana", PlayerBId: 2, PlayerB: "Bo", PlayerAClub: "Hillside"},
+	}
+	svc := &service{repo: repo}
+
+	// The fake repository has no players, so looking any of them up again
+	// would fail.
+	opponents, err := svc.opponentsFromMatch(match)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := opponents[0], opponents[1]
+	if a.Id != 5 || a.Name != "Ana & Bo" || !a.IsWinner {
+		t.Errorf("opponent A = %+v, want team 5 Ana & Bo as the winner", a)
+	}
+	if b.Id != 7 || b.Name != "Cai & Dev" || b.IsWinner {
+		t.Errorf("opponent B = %+v, want team 7 Cai & Dev", b)
+	}
+	if len(a.Players) != 2 || a.Players[0].Id != 1 || a.Players[0].Club != "Hillside" || a.Players[1].Name != "Bo" {
+		t.Errorf("opponent A players = %+v", a.Players)
+	}
+	if len(b.Players) != 2 || b.Players[1].Id != 4 || b.Players[1].Club != "Riverside" {
+		t.Errorf("opponent B players = %+v", b.Players)
+	}
+}
+
+func TestEndMatchNeedsBothOpponents(t *testing.T) {
+	repo := newFakeRepository(db.Match{Id: 1, Format: string(enums.Singles), Status: string(enums.Upcoming)})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 10, IsOpponentA: true})
+	svc := &service{repo: repo}
+
+	if _, err := svc.EndMatch(1, enums.Walkover, true); !errors.Is(err, ErrOpponentsNotDecided) {
+		t.Fatalf("EndMatch() error = %v, want %v", err, ErrOpponentsNotDecided)
+	}
+	if match := repo.findMatch(1); match.Status != string(enums.Upcoming) || match.Result != "" {
+		t.Errorf("match is %s %s, want still upcoming", match.Status, match.Result)
+	}
+
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 20, IsOpponentA: false})
+	if _, err := svc.EndMatch(1, enums.Walkover, true); err != nil {
+		t.Fatal(err)
+	}
+	if match := repo.findMatch(1); match.Status != string(enums.Past) || match.Result != string(enums.Walkover) {
+		t.Errorf("match is %s %s, want a walkover", match.Status, match.Result)
+	}
+}
@@ -1,9 +1,221 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"time"
+
 	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
 )
 
-func (s *service) CreatePlayer(name string) error {
-	return s.repo.CreatePlayer(&db.Player{Name: name})
+var ErrPlayerNotFound = errors.New("player not found")
+var ErrAmbiguousPlayerName = errors.New("more than one player registered with the name")
+var ErrPlayerNameRequired = errors.New("player name required")
+var ErrInvalidHandedness = errors.New("handedness must be RIGHT or LEFT")
+var ErrInvalidGripStyle = errors.New("grip style must be SHAKEHAND or PENHOLD")
+var ErrInvalidGender = errors.New("gender must be MALE, FEMALE or OTHER")
+var ErrInvalidBirthYear = errors.New("birth year must be between 1900 and this year")
+var ErrInvalidPhotoUrl = errors.New("photo url must be an absolute http or https url")
+var ErrPlayerInUse = errors.New("player has played matches, is part of a team or is entered into an event or season")
+
+// PlayerProfile holds the details of a player that can be edited. Every field
+// but the name may be left empty.
+type PlayerProfile struct {
+	Name       string
+	Club       string
+	Country    string
+	Handedness enums.Handedness
+	GripStyle  enums.GripStyle
+	BirthYear  *int
+	Gender     enums.Gender
+	PhotoUrl   string
+}
+
+type Player struct {
+	Id           int
+	TournamentId *int
+	PlayerProfile
+}
+
+// CreatePlayer registers a player, for the given tournament when tournamentId
+// is set.
+func (s *service) CreatePlayer(profile PlayerProfile, tournamentId *int) (int, error) {
+	if err := validatePlayerProfile(profile); err != nil {
+		return 0, err
+	}
+	if tournamentId != nil {
+		if _, err := s.getTournament(*tournamentId); err != nil {
+			return 0, err
+		}
+	}
+
+	player := newDbPlayer(profile)
+	player.TournamentId = tournamentId
+	id, err := s.repo.CreatePlayer(&player)
+	return int(id), err
+}
+
+// GetPlayers lists the players whose name contains the given text, ignoring
+// case, and only those of the tournament when tournamentId is set.
+func (s *service) GetPlayers(name string, tournamentId *int) ([]Player, error) {
+	playersFromDb, err := s.repo.SearchPlayers(name, tournamentId)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]Player, 0, len(playersFromDb))
+	for _, p := range playersFromDb {
+		players = append(players, newPlayer(p))
+	}
+	return players, nil
+}
+
+func (s *service) GetPlayer(playerId int) (*Player, error) {
+	p, err := s.getPlayer(playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	player := newPlayer(*p)
+	return &player, nil
+}
+
+// UpdatePlayer replaces the profile of the player. The tournament a player is
+// registered for cannot be changed.
+func (s *service) UpdatePlayer(playerId int, profile PlayerProfile) error {
+	if err := validatePlayerProfile(profile); err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *service) error {
+		p, err := tx.getPlayer(playerId)
+		if err != nil {
+			return err
+		}
+
+		player := newDbPlayer(profile)
+		player.Id = p.Id
+		player.TournamentId = p.TournamentId
+		return tx.repo.UpdatePlayer(&player)
+	})
+}
+
+// DeletePlayer removes a player that has not played any match, is not part
+// of any team and is not entered into any event or season.
+func (s *service) DeletePlayer(playerId int) error {
+	return s.inTx(func(tx *service) error {
+		if _, err := tx.getPlayer(playerId); err != nil {
+			return err
+		}
+		inUse, err := tx.repo.IsPlayerInUse(playerId)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrPlayerInUse
+		}
+		return tx.repo.DeletePlayer(playerId)
+	})
+}
+
+// FindPlayerId looks up a player by name, preferring the players registered
+// for the given tournament over the players not scoped to any tournament.
+func (s *service) FindPlayerId(name string, tournamentId *int) (int, error) {
+	players, err := s.repo.GetPlayersByName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	scoped := make([]int, 0)
+	unscoped := make([]int, 0)
+	for _, player := range players {
+		if player.TournamentId == nil {
+			unscoped = append(unscoped, player.Id)
+		} else if tournamentId != nil && *player.TournamentId == *tournamentId {
+			scoped = append(scoped, player.Id)
+		}
+	}
+
+	for _, candidates := range [][]int{scoped, unscoped} {
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		default:
+			return 0, ErrAmbiguousPlayerName
+		}
+	}
+	return 0, ErrPlayerNotFound
+}
+
+func (s *service) getPlayer(id int) (*db.Player, error) {
+	player, err := s.repo.GetPlayerById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPlayerNotFound
+	}
+	return player, err
+}
+
+func validatePlayerProfile(profile PlayerProfile) error {
+	if profile.Name == "" {
+		return ErrPlayerNameRequired
+	}
+	switch profile.Handedness {
+	case "", enums.RightHanded, enums.LeftHanded:
+	default:
+		return ErrInvalidHandedness
+	}
+	switch profile.GripStyle {
+	case "", enums.Shakehand, enums.Penhold:
+	default:
+		return ErrInvalidGripStyle
+	}
+	switch profile.Gender {
+	case "", enums.Male, enums.Female, enums.Other:
+	default:
+		return ErrInvalidGender
+	}
+	if profile.BirthYear != nil && (*profile.BirthYear < 1900 || *profile.BirthYear > time.Now().Year()) {
+		return ErrInvalidBirthYear
+	}
+	if profile.PhotoUrl != "" {
+		u, err := url.Parse(profile.PhotoUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidPhotoUrl
+		}
+	}
+	return nil
+}
+
+func newDbPlayer(profile PlayerProfile) db.Player {
+	return db.Player{
+		Name:       profile.Name,
+		Club:       profile.Club,
+		Country:    profile.Country,
+		Handedness: string(profile.Handedness),
+		GripStyle:  string(profile.GripStyle),
+		BirthYear:  profile.BirthYear,
+		Gender:     string(profile.Gender),
+		PhotoUrl:   profile.PhotoUrl,
+	}
+}
+
+func newPlayer(p db.Player) Player {
+	return Player{
+		Id:           p.Id,
+		TournamentId: p.TournamentId,
+		PlayerProfile: PlayerProfile{
+			Name:       p.Name,
+			Club:       p.Club,
+			Country:    p.Country,
+			Handedness: enums.Handedness(p.Handedness),
+			GripStyle:  enums.GripStyle(p.GripStyle),
+			BirthYear:  p.BirthYear,
+			Gender:     enums.Gender(p.Gender),
+			PhotoUrl:   p.PhotoUrl,
+		},
+	}
 }
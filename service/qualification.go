@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrNotPrelimsMatch = errors.New("only prelims matches can be played in a group")
+var ErrMatchNotInEvent = errors.New("match is not part of the event of the group")
+var ErrMatchInAnotherGroup = errors.New("match already belongs to another group")
+var ErrNoGroups = errors.New("event has no groups")
+var ErrGroupNotFinished = errors.New("group has unfinished matches")
+var ErrInvalidQualifierCount = errors.New("at least one opponent must qualify from each group")
+
+// qualifier is an opponent that finished a group in the given rank.
+type qualifier struct {
+	OpponentId int
+	Group      int
+	Rank       int
+}
+
+// AddMatchToGroup records that a prelims match of the event is played in the
+// group, adding its opponents to the group when they are not in it yet.
+func (s *service) AddMatchToGroup(groupId int, matchId int) error {
+	return s.inTx(func(tx *service) error {
+		group, err := tx.getGroup(groupId)
+		if err != nil {
+			return err
+		}
+		match, err := tx.repo.LockMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		if match.Stage != string(enums.Prelims) {
+			return ErrNotPrelimsMatch
+		}
+		if match.EventId == nil || *match.EventId != group.EventId {
+			return ErrMatchNotInEvent
+		}
+		if match.GroupId != nil {
+			if *match.GroupId == groupId {
+				return nil
+			}
+			return ErrMatchInAnotherGroup
+		}
+
+		entries, err := tx.repo.GetMatchGroupEntries(groupId)
+		if err != nil {
+			return err
+		}
+		inGroup := make(map[int]bool)
+		for _, entry := range entries {
+			inGroup[entry.OpponentId] = true
+		}
+		opponentIds, err := tx.opponentIdsFromMatch(*match)
+		if err != nil {
+			return err
+		}
+		for _, isOppA := range []bool{true, false} {
+			opponentId, ok := opponentIds[isOppA]
+			if !ok || inGroup[opponentId] {
+				continue
+			}
+			entries = append(entries, db.MatchGroupEntry{GroupId: groupId, OpponentId: opponentId, Position: len(entries) + 1})
+			if err := tx.repo.AddEntryToMatchGroup(&entries[len(entries)-1]); err != nil {
+				return err
+			}
+			inGroup[opponentId] = true
+		}
+
+		return tx.repo.UpdateMatchGroup(matchId, &groupId)
+	})
+}
+
+// QualifyFromGroups ranks every group of the event once all of its matches
+// are finished and draws the top qualifiersPerGroup of each into a knockout
+// bracket. Group winners are seeded in group order, so the winners of the
+// first two groups are in opposite halves. Every other qualifier is drawn as
+// far as possible from the opponents of its own group, which puts runners-up
+// in the opposite half to their group winner.
+func (s *service) QualifyFromGroups(eventId int, qualifiersPerGroup int, settings MatchSettings) error {
+	if qualifiersPerGroup < 1 {
+		return ErrInvalidQualifierCount
+	}
+
+	return s.inTx(func(tx *service) error {
+		if _, err := tx.getEvent(eventId); err != nil {
+			return err
+		}
+		groups, err := tx.repo.GetMatchGroupsByEventId(eventId)
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return ErrNoGroups
+		}
+
+		groupStandings := make([][]standing, 0, len(groups))
+		for _, group := range groups {
+			standings, err := tx.finishedGroupStandings(group.Id)
+			if err != nil {
+				return err
+			}
+			groupStandings = append(groupStandings, standings)
+		}
+
+		qualifiers := make([]qualifier, 0)
+		for rank := 1; rank <= qualifiersPerGroup; rank++ {
+			for i, standings := range groupStandings {
+				if rank <= len(standings) {
+					qualifiers = append(qualifiers, qualifier{OpponentId: standings[rank-1].OpponentId, Group: i, Rank: rank})
+				}
+			}
+		}
+		if len(qualifiers) < 2 {
+			return ErrNotEnoughEntries
+		}
+
+		return tx.createBracket(eventId, separatedDraw(qualifiers), enums.SingleElimination, settings)
+	})
+}
+
+func (s *service) finishedGroupStandings(groupId int) ([]standing, error) {
+	matches, err := s.repo.GetMatchesByGroupId(groupId)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if match.Status != string(enums.Past) {
+			return nil, ErrGroupNotFinished
+		}
+	}
+	return s.GetGroupStandings(groupId)
+}
+
+// separatedDraw places the qualifiers, ordered by rank and then group, into
+// the slots of a bracket. Group winners take the seeded slots and the byes go
+// against them. Each other qualifier takes the free slot where it would meet
+// the opponents of its own group as late as possible.
+func separatedDraw(qualifiers []qualifier) []int {
+	slots := seedPositions(bracketSize(len(qualifiers)))
+	winnerCount := 0
+	for _, q := range qualifiers {
+		if q.Rank == 1 {
+			winnerCount += 1
+		}
+	}
+
+	draw := make([]int, len(slots))
+	groupSlots := make(map[int][]int)
+	free := make([]int, 0)
+	for i, seed := range slots {
+		if seed <= winnerCount {
+			q := qualifiers[seed-1]
+			draw[i] = q.OpponentId
+			groupSlots[q.Group] = append(groupSlots[q.Group], i)
+		} else if seed <= len(qualifiers) {
+			free = append(free, i)
+		}
+	}
+
+	for _, q := range qualifiers[winnerCount:] {
+		best, bestRound := 0, -1
+		for i, slot := range free {
+			round := len(slots)
+			for _, other := range groupSlots[q.Group] {
+				if r := meetingRound(slot, other); r < round {
+					round = r
+				}
+			}
+			if round > bestRound {
+				best, bestRound = i, round
+			}
+		}
+
+		slot := free[best]
+		draw[slot] = q.OpponentId
+		groupSlots[q.Group] = append(groupSlots[q.Group], slot)
+		free = append(free[:best], free[best+1:]...)
+	}
+	return draw
+}
+
+// meetingRound returns the round in which the opponents in two bracket slots
+// would meet, counting the first round as 1.
+func meetingRound(slotA int, slotB int) int {
+	return bits.Len(uint(slotA ^ slotB))
+}
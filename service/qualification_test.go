@@ -0,0 +1,71 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMeetingRound(t *testing.T) {
+	tests := []struct {
+		slotA, slotB int
+		want         int
+	}{
+		{0, 1, 1},
+		{2, 3, 1},
+		{0, 2, 2},
+		{1, 3, 2},
+		{0, 4, 3},
+		{3, 4, 3},
+		{0, 15, 4},
+	}
+
+	for _, tt := range tests {
+		if got := meetingRound(tt.slotA, tt.slotB); got != tt.want {
+			t.Errorf("meetingRound(%d, %d) = %d, want %d", tt.slotA, tt.slotB, got, tt.want)
+		}
+	}
+}
+
+func TestSeparatedDraw(t *testing.T) {
+	// The opponent id of a qualifier is ten times its group number plus its
+	// rank, so 21 won the second group.
+	tests := []struct {
+		groups int
+		want   []int
+	}{
+		{2, []int{11, 22, 21, 12}},
+		{3, []int{11, 0, 22, 32, 21, 0, 31, 12}},
+		{4, []int{11, 22, 41, 32, 21, 12, 31, 42}},
+	}
+
+	for _, tt := range tests {
+		qualifiers := make([]qualifier, 0, 2*tt.groups)
+		for rank := 1; rank <= 2; rank++ {
+			for group := 0; group < tt.groups; group++ {
+				qualifiers = append(qualifiers, qualifier{OpponentId: 10*(group+1) + rank, Group: group, Rank: rank})
+			}
+		}
+
+		draw := separatedDraw(qualifiers)
+		if !reflect.DeepEqual(draw, tt.want) {
+			t.Errorf("%d groups: separatedDraw() = %v, want %v", tt.groups, draw, tt.want)
+		}
+
+		finalRound := meetingRound(0, len(draw)-1)
+		slots := make(map[int]int)
+		for slot, opponentId := range draw {
+			if opponentId != 0 {
+				slots[opponentId] = slot
+			}
+		}
+		for group := 1; group <= tt.groups; group++ {
+			winner, runnerUp := slots[10*group+1], slots[10*group+2]
+			if round := meetingRound(winner, runnerUp); round != finalRound {
+				t.Errorf("%d groups: group %d finishers meet in round %d, want the final", tt.groups, group, round)
+			}
+		}
+		if tt.groups >= 2 && meetingRound(slots[11], slots[21]) != finalRound {
+			t.Errorf("%d groups: winners of the first two groups are in the same half", tt.groups)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+const initialRating = 1500.0
+
+// Players and pairs move faster while their rating is provisional.
+const provisionalMatches = 30
+const provisionalKFactor = 32.0
+const establishedKFactor = 16.0
+
+type ranking struct {
+	Position     int
+	PlayerIds    []int
+	Name         string
+	Rating       float64
+	MatchesRated int
+}
+
+type ratingChange struct {
+	MatchId      int
+	RatingBefore float64
+	RatingAfter  float64
+	CreatedAt    time.Time
+}
+
+// GetRankings ranks the rated players for SINGLES or pairs of players for
+// DOUBLES by their Elo rating. A pair keeps one rating across every team it
+// played as.
+func (s *service) GetRankings(format enums.MatchFormat) ([]ranking, error) {
+	rows, err := s.repo.GetRankings(string(format))
+	if err != nil {
+		return nil, err
+	}
+
+	rankings := make([]ranking, 0, len(rows))
+	for i, row := range rows {
+		playerIds := []int{row.PlayerId}
+		if row.PartnerId != 0 {
+			playerIds = append(playerIds, row.PartnerId)
+		}
+		rankings = append(rankings, ranking{
+			Position:     i + 1,
+			PlayerIds:    playerIds,
+			Name:         row.Name,
+			Rating:       row.Rating,
+			MatchesRated: row.MatchesRated,
+		})
+	}
+	return rankings, nil
+}
+
+// GetPlayerRatingHistory lists every change to the singles rating of the
+// player, oldest first.
+func (s *service) GetPlayerRatingHistory(playerId int) ([]ratingChange, error) {
+	if _, err := s.getPlayer(playerId); err != nil {
+		return nil, err
+	}
+	changes, err := s.repo.GetRatingChangesByPlayer(string(enums.Singles), playerId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]ratingChange, 0, len(changes))
+	for _, c := range changes {
+		history = append(history, ratingChange{
+			MatchId:      c.MatchId,
+			RatingBefore: c.RatingBefore,
+			RatingAfter:  c.RatingAfter,
+			CreatedAt:    c.CreatedAt,
+		})
+	}
+	return history, nil
+}
+
+// ratingKey names the rating of a player for SINGLES, or of a pair of
+// players for DOUBLES with the lower player id first.
+type ratingKey struct {
+	PlayerId  int
+	PartnerId int
+}
+
+func pairRatingKey(playerId, partnerId int) ratingKey {
+	if playerId > partnerId {
+		playerId, partnerId = partnerId, playerId
+	}
+	return ratingKey{PlayerId: playerId, PartnerId: partnerId}
+}
+
+// ratingKeysFromMatch returns the rating of opponent A and of opponent B of
+// the match, keyed by whether the opponent is A.
+func (s *service) ratingKeysFromMatch(match db.Match) (map[bool]ratingKey, error) {
+	keys := make(map[bool]ratingKey)
+	if match.Format == string(enums.Doubles) {
+		rows, err := s.repo.GetTeamInfoByMatchId(match.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			keys[row.IsOpponentA] = pairRatingKey(row.PlayerAId, row.PlayerBId)
+		}
+		return keys, nil
+	}
+
+	opponentIds, err := s.opponentIdsFromMatch(match)
+	if err != nil {
+		return nil, err
+	}
+	for isOppA, id := range opponentIds {
+		keys[isOppA] = ratingKey{PlayerId: id}
+	}
+	return keys, nil
+}
+
+// rateMatch updates the Elo ratings of both opponents of a match that was
+// played out or retired from, and stores the change for each. Walkovers and
+// disqualifications are not rated.
+func (s *service) rateMatch(match *db.Match, winnerIsA bool, result enums.MatchResult) error {
+	if result != enums.Completed && result != enums.Retired {
+		return nil
+	}
+	keys, err := s.ratingKeysFromMatch(*match)
+	if err != nil {
+		return err
+	}
+	keyA, okA := keys[true]
+	keyB, okB := keys[false]
+	if !okA || !okB {
+		return nil
+	}
+
+	// Ratings are always locked in the same order so that two matches of the
+	// same opponents finishing together cannot deadlock.
+	ordered := []ratingKey{keyA, keyB}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].PlayerId != ordered[j].PlayerId {
+			return ordered[i].PlayerId < ordered[j].PlayerId
+		}
+		return ordered[i].PartnerId < ordered[j].PartnerId
+	})
+	ratings := make(map[ratingKey]*db.Rating)
+	for _, key := range ordered {
+		rating, err := s.repo.LockRating(match.Format, key.PlayerId, key.PartnerId, initialRating)
+		if err != nil {
+			return err
+		}
+		ratings[key] = rating
+	}
+
+	a, b := ratings[keyA], ratings[keyB]
+	scoreA := 0.0
+	if winnerIsA {
+		scoreA = 1
+	}
+	expectedA := 1 / (1 + math.Pow(10, (b.Rating-a.Rating)/400))
+	deltas := map[*db.Rating]float64{
+		a: kFactor(*a) * (scoreA - expectedA),
+		b: kFactor(*b) * ((1 - scoreA) - (1 - expectedA)),
+	}
+
+	for _, rating := range []*db.Rating{a, b} {
+		before := rating.Rating
+		rating.Rating += deltas[rating]
+		rating.MatchesRated += 1
+		if err := s.repo.UpdateRating(rating); err != nil {
+			return err
+		}
+		err := s.repo.CreateRatingChange(&db.RatingChange{
+			MatchId:      match.Id,
+			Format:       match.Format,
+			PlayerId:     rating.PlayerId,
+			PartnerId:    rating.PartnerId,
+			RatingBefore: before,
+			RatingAfter:  rating.Rating,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unrateMatch takes the rating changes of a match back out of the ratings,
+// keeping the changes of every match rated since. Those are not worked out
+// again, so the ratings can drift from rating the remaining results afresh.
+func (s *service) unrateMatch(match *db.Match) error {
+	changes, err := s.repo.GetRatingChangesByMatchId(match.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		rating, err := s.repo.LockRating(change.Format, change.PlayerId, change.PartnerId, initialRating)
+		if err != nil {
+			return err
+		}
+		rating.Rating -= change.RatingAfter - change.RatingBefore
+		rating.MatchesRated -= 1
+		if err := s.repo.UpdateRating(rating); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteRatingChangesByMatchId(match.Id)
+}
+
+func kFactor(rating db.Rating) float64 {
+	if rating.MatchesRated < provisionalMatches {
+		return provisionalKFactor
+	}
+	return establishedKFactor
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+// newRatedMatches returns a repository with a singles match between each
+// pair of players, in order.
+func newRatedMatches(pairs ...[2]int) (*fakeRepository, *service) {
+	repo := newFakeRepository()
+	for _, pair := range pairs {
+		id := repo.nextId()
+		repo.matches = append(repo.matches, db.Match{Id: id, Format: string(enums.Singles), Status: string(enums.Ongoing)})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: pair[0], IsOpponentA: true})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: pair[1], IsOpponentA: false})
+	}
+	return repo, &service{repo: repo}
+}
+
+func ratingOf(repo *fakeRepository, format enums.MatchFormat, playerId, partnerId int) db.Rating {
+	for _, rating := range repo.ratings {
+		if rating.Format == string(format) && rating.PlayerId == playerId && rating.PartnerId == partnerId {
+			return rating
+		}
+	}
+	return db.Rating{Format: string(format), PlayerId: playerId, PartnerId: partnerId, Rating: initialRating}
+}
+
+func TestRateAndUnrateMatchAreSymmetric(t *testing.T) {
+	repo, svc := newRatedMatches([2]int{1, 2})
+	match := repo.findMatch(1)
+
+	if err := svc.rateMatch(match, true, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+	winner, loser := ratingOf(repo, enums.Singles, 1, 0), ratingOf(repo, enums.Singles, 2, 0)
+	if winner.Rating != initialRating+provisionalKFactor/2 || loser.Rating != initialRating-provisionalKFactor/2 {
+		t.Errorf("ratings after the match = %v and %v, want %v either side of %v",
+			winner.Rating, loser.Rating, provisionalKFactor/2, initialRating)
+	}
+	if len(repo.ratingChanges) != 2 {
+		t.Errorf("%d rating changes stored, want 2", len(repo.ratingChanges))
+	}
+
+	if err := svc.unrateMatch(match); err != nil {
+		t.Fatal(err)
+	}
+	for _, playerId := range []int{1, 2} {
+		if rating := ratingOf(repo, enums.Singles, playerId, 0); rating.Rating != initialRating || rating.MatchesRated != 0 {
+			t.Errorf("player %d rating after undo = %+v, want %v over 0 matches", playerId, rating, initialRating)
+		}
+	}
+	if len(repo.ratingChanges) != 0 {
+		t.Errorf("%d rating changes left after undo, want 0", len(repo.ratingChanges))
+	}
+
+	if err := svc.rateMatch(match, true, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+	if got := ratingOf(repo, enums.Singles, 1, 0); got.Rating != winner.Rating || got.MatchesRated != 1 {
+		t.Errorf("rating after redo = %+v, want %+v", got, winner)
+	}
+}
+
+func TestUnrateEarlierMatchKeepsLaterChanges(t *testing.T) {
+	repo, svc := newRatedMatches([2]int{1, 2}, [2]int{1, 3})
+	first, second := repo.findMatch(1), repo.findMatch(2)
+
+	if err := svc.rateMatch(first, true, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.rateMatch(second, true, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+	secondChange := ratingOf(repo, enums.Singles, 3, 0).Rating - initialRating
+
+	if err := svc.unrateMatch(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second match was rated with player 1 above the initial rating, so
+	// its change is kept as it was rather than worked out again.
+	if got := ratingOf(repo, enums.Singles, 1, 0).Rating; math.Abs(got-(initialRating-secondChange)) > 1e-9 {
+		t.Errorf("player 1 rating = %v, want %v", got, initialRating-secondChange)
+	}
+	if got := ratingOf(repo, enums.Singles, 2, 0); got.Rating != initialRating || got.MatchesRated != 0 {
+		t.Errorf("player 2 rating = %+v, want the initial rating", got)
+	}
+	if secondChange == -provisionalKFactor/2 {
+		t.Errorf("second match change = %v, want it to differ from a match between equal ratings", secondChange)
+	}
+}
+
+func TestDoublesRatingsFollowThePairAcrossTeams(t *testing.T) {
+	repo := newFakeRepository(
+		db.Match{Id: 1, Format: string(enums.Doubles), Status: string(enums.Ongoing)},
+		db.Match{Id: 2, Format: string(enums.Doubles), Status: string(enums.Ongoing)},
+	)
+	repo.teamRows = []db.TeamInfoByMatchIdRow{
+		{MatchId: 1, TeamId: 10, IsOpponentA: true, PlayerAId: 4, PlayerBId: 2},
+		{MatchId: 1, TeamId: 11, IsOpponentA: false, PlayerAId: 5, PlayerBId: 6},
+		{MatchId: 2, TeamId: 20, IsOpponentA: true, PlayerAId: 7, PlayerBId: 8},
+		{MatchId: 2, TeamId: 21, IsOpponentA: false, PlayerAId: 2, PlayerBId: 4},
+	}
+	svc := &service{repo: repo}
+
+	if err := svc.rateMatch(repo.findMatch(1), true, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.rateMatch(repo.findMatch(2), false, enums.Completed); err != nil {
+		t.Fatal(err)
+	}
+
+	pair := ratingOf(repo, enums.Doubles, 2, 4)
+	if pair.MatchesRated != 2 || pair.Rating <= initialRating+provisionalKFactor/2 {
+		t.Errorf("pair rating = %+v, want both wins counted", pair)
+	}
+	for _, rating := range repo.ratings {
+		if rating.PlayerId > rating.PartnerId {
+			t.Errorf("rating %+v keyed with the higher player id first", rating)
+		}
+	}
+}
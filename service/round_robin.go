@@ -0,0 +1,160 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+type group struct {
+	Id          int
+	EventId     int
+	Name        string
+	OpponentIds []int
+}
+
+// CreateGroup draws the opponents into a round-robin group of the event, in
+// the given order, and creates its fixtures from the Berger tables. A group
+// created without opponents is filled by adding existing matches to it.
+func (s *service) CreateGroup(eventId int, name string, opponentIds []int, settings MatchSettings) (int, error) {
+	var groupId int
+	err := s.inTx(func(tx *service) error {
+		id, err := tx.createGroup(eventId, name, opponentIds, settings)
+		groupId = id
+		return err
+	})
+	return groupId, err
+}
+
+func (s *service) createGroup(eventId int, name string, opponentIds []int, settings MatchSettings) (int, error) {
+	event, err := s.getEvent(eventId)
+	if err != nil {
+		return 0, err
+	}
+	if len(opponentIds) == 1 {
+		return 0, ErrNotEnoughEntries
+	}
+	seen := make(map[int]bool)
+	for _, id := range opponentIds {
+		if seen[id] {
+			return 0, ErrDuplicateEntry
+		}
+		seen[id] = true
+	}
+
+	settings.EventId = &eventId
+	format := enums.MatchFormat(event.Format)
+	template, err := s.newMatch(format, enums.Prelims, settings)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.CreateMatchGroup(&db.MatchGroup{EventId: eventId, Name: name})
+	if err != nil {
+		return 0, err
+	}
+	groupId := int(id)
+
+	for i, opponentId := range opponentIds {
+		err := s.repo.AddEntryToMatchGroup(&db.MatchGroupEntry{GroupId: groupId, OpponentId: opponentId, Position: i + 1})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i, round := range bergerRounds(len(opponentIds)) {
+		groupRound := i + 1
+		for _, pairing := range round {
+			match := *template
+			match.GroupId = &groupId
+			match.GroupRound = &groupRound
+			matchId, err := s.repo.CreateMatch(&match)
+			if err != nil {
+				return 0, err
+			}
+			match.Id = int(matchId)
+
+			if err := s.addOpponentToMatch(match, opponentIds[pairing[0]], true); err != nil {
+				return 0, err
+			}
+			if err := s.addOpponentToMatch(match, opponentIds[pairing[1]], false); err != nil {
+				return 0, err
+			}
+			if err := s.checkOpponentsInEvent(match.Id, format, &eventId); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return groupId, nil
+}
+
+func (s *service) GetGroups(eventId int) ([]group, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+
+	groupsFromDb, err := s.repo.GetMatchGroupsByEventId(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]group, 0, len(groupsFromDb))
+	for _, g := range groupsFromDb {
+		entries, err := s.repo.GetMatchGroupEntries(g.Id)
+		if err != nil {
+			return nil, err
+		}
+		opponentIds := make([]int, 0, len(entries))
+		for _, entry := range entries {
+			opponentIds = append(opponentIds, entry.OpponentId)
+		}
+		groups = append(groups, group{Id: g.Id, EventId: g.EventId, Name: g.Name, OpponentIds: opponentIds})
+	}
+	return groups, nil
+}
+
+func (s *service) getGroup(id int) (*db.MatchGroup, error) {
+	group, err := s.repo.GetMatchGroupById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	return group, err
+}
+
+// bergerRounds returns the pairings of every round of a round robin between
+// count entries, as indexes into the entry list. With an odd count the entry
+// drawn against the missing last entry sits the round out. The last entry
+// stays in place while the others rotate, and alternates between playing as
+// opponent A and B so that nobody is always listed first.
+func bergerRounds(count int) [][][2]int {
+	n := count
+	if n%2 == 1 {
+		n += 1
+	}
+
+	rounds := make([][][2]int, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		round := make([][2]int, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			a := (r + i) % (n - 1)
+			b := (r + n - 1 - i) % (n - 1)
+			if i == 0 {
+				b = n - 1
+				if r%2 == 1 {
+					a, b = b, a
+				}
+			}
+			if a >= count || b >= count {
+				continue
+			}
+			round = append(round, [2]int{a, b})
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds
+}
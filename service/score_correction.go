@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrCorrectionReasonRequired = errors.New("correction reason required")
+var ErrInvalidCorrection = errors.New("invalid score correction")
+
+// CorrectSetScore overwrites the score of a set and records the reason as a
+// score correction event. The corrected score must be one the scoring rules
+// of the match can reach. Completion of the set and of the match is worked
+// out again from the corrected score. A completed match is only reopened when
+// the correction changes its winner, which like Undo is refused once a next
+// bracket match has started. Only the last set may be left unfinished by a
+// correction, and Undo does not step back past it.
+func (s *service) CorrectSetScore(matchId int, setId int, oppAScore int, oppBScore int, reason string) ([]int, error) {
+	if reason == "" {
+		return nil, ErrCorrectionReasonRequired
+	}
+	if oppAScore < 0 || oppBScore < 0 {
+		return nil, ErrInvalidCorrection
+	}
+
+	return s.inTxChanging(func(tx *service) error {
+		match, err := tx.repo.LockMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		sets, err := tx.repo.GetSetsByMatchId(matchId)
+		if err != nil {
+			return err
+		}
+
+		var set *db.Set
+		for i := range sets {
+			if sets[i].Id == setId {
+				set = &sets[i]
+			}
+		}
+		if set == nil {
+			return ErrSetNotFound
+		}
+
+		winnerWasA, _ := matchWinner(*match, sets)
+
+		detail := fmt.Sprintf(
+			"%d-%d corrected to %d-%d: %s",
+			set.OpponentAScore, set.OpponentBScore, oppAScore, oppBScore, reason,
+		)
+
+		set.OpponentAScore = oppAScore
+		set.OpponentBScore = oppBScore
+		if !isReachableScore(*match, *set) {
+			return ErrInvalidCorrection
+		}
+		set.IsCompleted = rulesForMatch(*match).IsSetComplete(*set, match.GamePoint)
+		if !set.IsCompleted && set.SetNumber != len(sets) {
+			return ErrInvalidCorrection
+		}
+
+		err = tx.repo.UpdateSet(set)
+		if err != nil {
+			return err
+		}
+		err = tx.repo.CreateMatchEvent(&db.MatchEvent{
+			MatchId: matchId,
+			SetId:   &set.Id,
+			Type:    string(enums.ScoreCorrection),
+			Detail:  detail,
+		})
+		if err != nil {
+			return err
+		}
+		err = tx.repo.ClearScoreRedos(matchId)
+		if err != nil {
+			return err
+		}
+
+		if match.Status == string(enums.Past) {
+			if match.Result != string(enums.Completed) {
+				return nil
+			}
+			winnerIsA, decided := matchWinner(*match, sets)
+			if decided && winnerIsA == winnerWasA {
+				return nil
+			}
+			err = tx.reopenMatch(match)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.handleMatchCompletion(match)
+	})
+}
+
+// isReachableScore reports whether the score of the set can come about point
+// by point: no opponent is below the handicap they started with, and a
+// finished set was not already finished a point earlier.
+func isReachableScore(match db.Match, set db.Set) bool {
+	if set.OpponentAScore < match.HandicapA || set.OpponentBScore < match.HandicapB {
+		return false
+	}
+	rules := rulesForMatch(match)
+	if !rules.IsSetComplete(set, match.GamePoint) {
+		return true
+	}
+
+	before := set
+	switch {
+	case set.OpponentAScore > set.OpponentBScore:
+		before.OpponentAScore -= 1
+	case set.OpponentBScore > set.OpponentAScore:
+		before.OpponentBScore -= 1
+	default:
+		return false
+	}
+	if before.OpponentAScore < match.HandicapA || before.OpponentBScore < match.HandicapB {
+		return false
+	}
+	return !rules.IsSetComplete(before, match.GamePoint)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestIsReachableScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     enums.ScoringRuleSet
+		handicapA int
+		a, b      int
+		want      bool
+	}{
+		{"finished set", enums.StandardRules, 0, 11, 9, true},
+		{"finished past deuce", enums.StandardRules, 0, 12, 10, true},
+		{"unfinished set", enums.StandardRules, 0, 7, 4, true},
+		{"level at deuce", enums.StandardRules, 0, 11, 11, true},
+		{"scored on after winning", enums.StandardRules, 0, 30, 2, false},
+		{"three points clear past deuce", enums.StandardRules, 0, 13, 10, false},
+		{"golden point", enums.GoldenPointRules, 0, 11, 10, true},
+		{"level after golden point", enums.GoldenPointRules, 0, 11, 11, false},
+		{"level at capped game point", enums.CappedRules, 0, 11, 11, false},
+		{"below handicap", enums.StandardRules, 3, 2, 0, false},
+		{"won from handicap", enums.StandardRules, 3, 11, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := db.Match{GamePoint: 11, ScoringRules: string(tt.rules), HandicapA: tt.handicapA}
+			set := db.Set{OpponentAScore: tt.a, OpponentBScore: tt.b}
+			if got := isReachableScore(match, set); got != tt.want {
+				t.Errorf("isReachableScore(%d-%d) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndoAfterCorrection(t *testing.T) {
+	repo := newFakeRepository(db.Match{
+		Id:             1,
+		Format:         string(enums.Singles),
+		GamePoint:      11,
+		SetCount:       5,
+		Status:         string(enums.Ongoing),
+		ScoringRules:   string(enums.StandardRules),
+		FirstServerIsA: true,
+	})
+	setId, _ := repo.CreateSet(&db.Set{SetNumber: 1, MatchId: 1, StartedAt: time.Now()})
+	svc := &service{repo: repo}
+
+	score := func(scoredByA bool) {
+		t.Helper()
+		if _, err := svc.UpdateScore(1, int(setId), scoredByA, false, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertScore := func(a, b int) {
+		t.Helper()
+		set := repo.sets[0]
+		if set.OpponentAScore != a || set.OpponentBScore != b {
+			t.Fatalf("score = %d-%d, want %d-%d", set.OpponentAScore, set.OpponentBScore, a, b)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		score(true)
+	}
+	for i := 0; i < 3; i++ {
+		score(false)
+	}
+	if _, err := svc.CorrectSetScore(1, int(setId), 6, 3, "point missed"); err != nil {
+		t.Fatal(err)
+	}
+	score(false)
+	assertScore(6, 4)
+
+	if _, err := svc.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+	assertScore(6, 3)
+
+	if _, err := svc.Undo(1); !errors.Is(err, ErrUndoPastCorrection) {
+		t.Fatalf("Undo() error = %v, want %v", err, ErrUndoPastCorrection)
+	}
+	assertScore(6, 3)
+}
+
+func TestCorrectSetScoreRejectsUnreachableScore(t *testing.T) {
+	repo := newFakeRepository(db.Match{
+		Id:           1,
+		Format:       string(enums.Singles),
+		GamePoint:    11,
+		SetCount:     5,
+		Status:       string(enums.Ongoing),
+		ScoringRules: string(enums.StandardRules),
+	})
+	setId, _ := repo.CreateSet(&db.Set{SetNumber: 1, MatchId: 1, StartedAt: time.Now()})
+	svc := &service{repo: repo}
+
+	if _, err := svc.CorrectSetScore(1, int(setId), 30, 2, "typo"); !errors.Is(err, ErrInvalidCorrection) {
+		t.Fatalf("CorrectSetScore() error = %v, want %v", err, ErrInvalidCorrection)
+	}
+}
+
+func TestCorrectSetScoreReopensMatchOnlyWhenTheWinnerChanges(t *testing.T) {
+	nextMatchId, slotIsA := 2, true
+	repo := newFakeRepository(
+		db.Match{
+			Id:               1,
+			Format:           string(enums.Singles),
+			GamePoint:        11,
+			SetCount:         3,
+			Status:           string(enums.Past),
+			Result:           string(enums.Completed),
+			ScoringRules:     string(enums.StandardRules),
+			NextMatchId:      &nextMatchId,
+			NextMatchSlotIsA: &slotIsA,
+		},
+		db.Match{Id: 2, Format: string(enums.Singles), Status: string(enums.Ongoing)},
+	)
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 10, IsOpponentA: true})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 20, IsOpponentA: false})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 2, PlayerId: 10, IsOpponentA: true})
+	repo.UpdateMatchWinner(&db.Match{Id: 1}, true)
+	setId, _ := repo.CreateSet(&db.Set{SetNumber: 1, MatchId: 1, OpponentAScore: 11, OpponentBScore: 5, IsCompleted: true})
+	repo.CreateSet(&db.Set{SetNumber: 2, MatchId: 1, OpponentAScore: 11, OpponentBScore: 5, IsCompleted: true})
+	svc := &service{repo: repo}
+
+	if _, err := svc.CorrectSetScore(1, int(setId), 11, 9, "scored the wrong side"); err != nil {
+		t.Fatalf("CorrectSetScore() keeping the winner error = %v", err)
+	}
+	if match := repo.findMatch(1); match.Status != string(enums.Past) || match.Result != string(enums.Completed) {
+		t.Errorf("match is %s %s, want still completed", match.Status, match.Result)
+	}
+	if opponents := repo.opponentsOf(2); opponents[0] != 10 {
+		t.Errorf("next match opponents = %v, want the winner kept in slot A", opponents)
+	}
+
+	if _, err := svc.CorrectSetScore(1, int(setId), 9, 11, "scored the wrong side"); !errors.Is(err, ErrNextMatchStarted) {
+		t.Fatalf("CorrectSetScore() changing the winner error = %v, want %v", err, ErrNextMatchStarted)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 11 || set.OpponentBScore != 9 {
+		t.Errorf("set is %d-%d after the refused correction, want 11-9 kept", set.OpponentAScore, set.OpponentBScore)
+	}
+	if len(repo.events) != 1 {
+		t.Errorf("%d events after the refused correction, want only the first correction", len(repo.events))
+	}
+}
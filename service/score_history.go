@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrNoScoreToRedo = errors.New("no score to redo")
+var ErrUndoPastCorrection = errors.New("cannot undo points scored before a score correction")
+
+// Undo removes the last point of the match. Finished sets are reopened, an
+// empty trailing set is deleted, its umpire events moving to the previous
+// set, and a completed match is reverted to ongoing. Points scored before the
+// latest score correction of a set cannot be undone. A match ended by a
+// walkover, retirement or disqualification only has that result undone and
+// keeps its score.
+// The point can be replayed with Redo until a new point is scored.
+func (s *service) Undo(matchId int) ([]int, error) {
+	return s.inTxChanging(func(tx *service) error {
+		match, err := tx.repo.LockMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		sets, err := tx.repo.GetSetsByMatchId(matchId)
+		if err != nil {
+			return err
+		}
+		return tx.undoLastPoint(match, sets)
+	})
+}
+
+// Redo replays the last undone point of the match, opening its set again if
+// the undo deleted it.
+func (s *service) Redo(matchId int) ([]int, error) {
+	return s.inTxChanging(func(tx *service) error {
+		_, err := tx.repo.LockMatchById(matchId)
+		if err != nil {
+			return err
+		}
+		redo, err := tx.repo.PopScoreRedo(matchId)
+		if err != nil {
+			return err
+		}
+		if redo == nil {
+			return ErrNoScoreToRedo
+		}
+
+		sets, err := tx.repo.GetSetsByMatchId(matchId)
+		if err != nil {
+			return err
+		}
+		if len(sets) < redo.SetNumber {
+			err = tx.createSet(matchId, nil)
+			if err != nil {
+				return err
+			}
+			sets, err = tx.repo.GetSetsByMatchId(matchId)
+			if err != nil {
+				return err
+			}
+		}
+
+		match, set, err := tx.getOpenSet(matchId, sets[redo.SetNumber-1].Id)
+		if err != nil {
+			return err
+		}
+		err = tx.scorePoint(match, set, redo.ScoredByA)
+		if err != nil {
+			return err
+		}
+		return tx.handleChangeOfEnds(match, set, false)
+	})
+}
+
+func (s *service) undoLastPoint(match *db.Match, sets []db.Set) error {
+	if match.Status == string(enums.Past) && match.Result != string(enums.Completed) {
+		err := s.reopenMatch(match)
+		if err != nil {
+			return err
+		}
+		if len(sets) == 0 {
+			return s.repo.UpdateMatchStatus(match.Id, string(enums.Upcoming))
+		}
+		return nil
+	}
+
+	correctedAt, err := s.correctionTimes(match.Id)
+	if err != nil {
+		return err
+	}
+
+	for len(sets) > 0 {
+		latestSet := sets[len(sets)-1]
+		limit := 1
+		setLogs, err := s.repo.GetSetLogsBySetId(latestSet.Id, &limit)
+		if err != nil {
+			return err
+		}
+
+		if t, ok := correctedAt[latestSet.Id]; ok && (len(setLogs) == 0 || !setLogs[0].CreatedAt.After(t)) {
+			return ErrUndoPastCorrection
+		}
+
+		if len(setLogs) == 0 {
+			if len(sets) == 1 {
+				return ErrNoScoreToUndo
+			}
+			// Only the change of ends belongs to the set being removed. The
+			// umpire's other events stay on record with the previous set.
+			changeEnds := string(enums.ChangeEnds)
+			err = s.repo.DeleteMatchEventsBySetId(latestSet.Id, &changeEnds)
+			if err != nil {
+				return err
+			}
+			err = s.repo.MoveMatchEventsToSet(latestSet.Id, sets[len(sets)-2].Id)
+			if err != nil {
+				return err
+			}
+			err = s.repo.DeleteSet(latestSet.Id)
+			if err != nil {
+				return err
+			}
+			sets = sets[:len(sets)-1]
+			continue
+		}
+
+		err = s.repo.DeleteSetLog(setLogs[0].Id)
+		if err != nil {
+			return err
+		}
+		// The score is taken back from the set itself rather than the previous
+		// log, which predates any correction made since.
+		if setLogs[0].ScoredByA {
+			latestSet.OpponentAScore -= 1
+		} else {
+			latestSet.OpponentBScore -= 1
+		}
+		latestSet.IsCompleted = false
+		err = s.repo.UpdateSet(&latestSet)
+		if err != nil {
+			return err
+		}
+
+		scoredByA, scoredByB := pointsScored(*match, latestSet.OpponentAScore, latestSet.OpponentBScore)
+		if latestSet.SetNumber != match.SetCount ||
+			(scoredByA < DecidingSetChangeEndsScore && scoredByB < DecidingSetChangeEndsScore) {
+			changeEnds := string(enums.ChangeEnds)
+			err = s.repo.DeleteMatchEventsBySetId(latestSet.Id, &changeEnds)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.repo.PushScoreRedo(&db.ScoreRedo{
+			MatchId:   match.Id,
+			SetNumber: latestSet.SetNumber,
+			ScoredByA: setLogs[0].ScoredByA,
+		})
+		if err != nil {
+			return err
+		}
+
+		if match.Status == string(enums.Past) {
+			return s.reopenMatch(match)
+		}
+		return nil
+	}
+
+	return ErrNoScoreToUndo
+}
+
+// correctionTimes returns when the score of each set of the match was last
+// corrected.
+func (s *service) correctionTimes(matchId int) (map[int]time.Time, error) {
+	events, err := s.repo.GetMatchEventsByMatchId(matchId)
+	if err != nil {
+		return nil, err
+	}
+
+	correctedAt := make(map[int]time.Time)
+	for _, e := range events {
+		if e.Type != string(enums.ScoreCorrection) || e.SetId == nil {
+			continue
+		}
+		if t, ok := correctedAt[*e.SetId]; !ok || e.CreatedAt.After(t) {
+			correctedAt[*e.SetId] = e.CreatedAt
+		}
+	}
+	return correctedAt, nil
+}
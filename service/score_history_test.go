@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+// newScoringFixture returns a singles match between players 10 and 20 with
+// its first set open.
+func newScoringFixture(t *testing.T, setCount int) (*fakeRepository, *service) {
+	t.Helper()
+	repo := newFakeRepository(db.Match{
+		Id:             1,
+		Format:         string(enums.Singles),
+		GamePoint:      11,
+		SetCount:       setCount,
+		Status:         string(enums.Upcoming),
+		ScoringRules:   string(enums.StandardRules),
+		FirstServerIsA: true,
+	})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 10, IsOpponentA: true})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 20, IsOpponentA: false})
+	svc := &service{repo: repo}
+	if err := svc.CreateSet(1, nil); err != nil {
+		t.Fatal(err)
+	}
+	return repo, svc
+}
+
+func TestUndoAcrossSetBoundary(t *testing.T) {
+	repo, svc := newScoringFixture(t, 5)
+	firstSetId := repo.sets[0].Id
+	for i := 0; i < 11; i++ {
+		if _, err := svc.UpdateScore(1, firstSetId, true, true, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(repo.sets) != 2 {
+		t.Fatalf("%d sets after the first was won, want the next set opened", len(repo.sets))
+	}
+	isOppA := true
+	if err := svc.RecordMatchEvent(1, enums.Timeout, &isOppA, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.sets) != 1 {
+		t.Fatalf("%d sets after undo, want the empty trailing set deleted", len(repo.sets))
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 10 || set.OpponentBScore != 0 || set.IsCompleted {
+		t.Errorf("first set is %d-%d completed %v, want 10-0 and open", set.OpponentAScore, set.OpponentBScore, set.IsCompleted)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("events = %+v, want only the timeout", repo.events)
+	}
+	if e := repo.events[0]; e.Type != string(enums.Timeout) || e.SetId == nil || *e.SetId != firstSetId {
+		t.Errorf("timeout = %+v, want it moved to the first set", e)
+	}
+
+	if _, err := svc.Redo(1); err != nil {
+		t.Fatal(err)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 11 || !set.IsCompleted {
+		t.Errorf("first set is %d-%d completed %v after redo, want it won again", set.OpponentAScore, set.OpponentBScore, set.IsCompleted)
+	}
+}
+
+func TestUndoOfRetirementKeepsTheScore(t *testing.T) {
+	repo, svc := newScoringFixture(t, 5)
+	setId := repo.sets[0].Id
+	for _, scoredByA := range []bool{true, false, true} {
+		if _, err := svc.UpdateScore(1, setId, scoredByA, false, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.EndMatch(1, enums.Retired, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if match := repo.findMatch(1); match.Status != string(enums.Ongoing) || match.Result != "" {
+		t.Errorf("match is %s %q, want ongoing without a result", match.Status, match.Result)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 2 || set.OpponentBScore != 1 {
+		t.Errorf("score = %d-%d, want 2-1 kept", set.OpponentAScore, set.OpponentBScore)
+	}
+	if len(repo.setLogs) != 3 || len(repo.redos) != 0 {
+		t.Errorf("%d points and %d redos, want all 3 points kept and nothing to redo", len(repo.setLogs), len(repo.redos))
+	}
+}
+
+func TestUndoOfWalkoverReopensUpcomingMatch(t *testing.T) {
+	repo := newFakeRepository(db.Match{Id: 1, Format: string(enums.Singles), SetCount: 5, Status: string(enums.Upcoming)})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 10, IsOpponentA: true})
+	repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: 1, PlayerId: 20, IsOpponentA: false})
+	svc := &service{repo: repo}
+	if _, err := svc.EndMatch(1, enums.Walkover, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if match := repo.findMatch(1); match.Status != string(enums.Upcoming) || match.Result != "" {
+		t.Errorf("match is %s %q, want upcoming again", match.Status, match.Result)
+	}
+}
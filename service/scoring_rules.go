@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrUnknownScoringRules = errors.New("unknown scoring rules")
+
+// ScoringRules decides when a set and a match are won.
+type ScoringRules interface {
+	IsSetComplete(set db.Set, gamePoint int) bool
+	HasMajorityWins(wins int, setCount int) bool
+}
+
+var scoringRules = map[enums.ScoringRuleSet]ScoringRules{
+	enums.StandardRules:    standardRules{},
+	enums.GoldenPointRules: goldenPointRules{},
+	enums.CappedRules:      cappedRules{},
+}
+
+// ScoringRulesFor returns the built-in rule set registered under name.
+func ScoringRulesFor(name enums.ScoringRuleSet) (ScoringRules, error) {
+	rules, ok := scoringRules[name]
+	if !ok {
+		return nil, ErrUnknownScoringRules
+	}
+	return rules, nil
+}
+
+func rulesForMatch(match db.Match) ScoringRules {
+	rules, err := ScoringRulesFor(enums.ScoringRuleSet(match.ScoringRules))
+	if err != nil {
+		return standardRules{}
+	}
+	return rules
+}
+
+// standardRules needs a lead of two points once the game point is reached.
+type standardRules struct{}
+
+func (standardRules) IsSetComplete(set db.Set, gamePoint int) bool {
+	return maxScore(set) >= gamePoint && scoreDiff(set) > 1
+}
+
+func (standardRules) HasMajorityWins(wins int, setCount int) bool {
+	return (wins*100)/setCount > 50
+}
+
+// goldenPointRules plays a single deciding point once both opponents are one
+// point short of the game point.
+type goldenPointRules struct {
+	standardRules
+}
+
+func (goldenPointRules) IsSetComplete(set db.Set, gamePoint int) bool {
+	if maxScore(set) < gamePoint {
+		return false
+	}
+	return scoreDiff(set) > 1 || minScore(set) >= gamePoint-1
+}
+
+// cappedRules ends the set as soon as an opponent reaches the game point.
+type cappedRules struct {
+	standardRules
+}
+
+func (cappedRules) IsSetComplete(set db.Set, gamePoint int) bool {
+	return maxScore(set) >= gamePoint
+}
+
+func scoreDiff(set db.Set) int {
+	if set.OpponentAScore > set.OpponentBScore {
+		return set.OpponentAScore - set.OpponentBScore
+	}
+	return set.OpponentBScore - set.OpponentAScore
+}
+
+func maxScore(set db.Set) int {
+	if set.OpponentAScore > set.OpponentBScore {
+		return set.OpponentAScore
+	}
+	return set.OpponentBScore
+}
+
+func minScore(set db.Set) int {
+	if set.OpponentAScore < set.OpponentBScore {
+		return set.OpponentAScore
+	}
+	return set.OpponentBScore
+}
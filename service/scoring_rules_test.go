@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestIsSetComplete(t *testing.T) {
+	tests := []struct {
+		rules enums.ScoringRuleSet
+		a, b  int
+		want  bool
+	}{
+		{enums.StandardRules, 10, 8, false},
+		{enums.StandardRules, 11, 9, true},
+		{enums.StandardRules, 11, 10, false},
+		{enums.StandardRules, 12, 10, true},
+		{enums.GoldenPointRules, 11, 9, true},
+		{enums.GoldenPointRules, 10, 10, false},
+		{enums.GoldenPointRules, 11, 10, true},
+		{enums.GoldenPointRules, 10, 11, true},
+		{enums.CappedRules, 10, 10, false},
+		{enums.CappedRules, 11, 10, true},
+	}
+
+	for _, tt := range tests {
+		rules, err := ScoringRulesFor(tt.rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := db.Set{OpponentAScore: tt.a, OpponentBScore: tt.b}
+		if got := rules.IsSetComplete(set, 11); got != tt.want {
+			t.Errorf("%s IsSetComplete(%d-%d) = %v, want %v", tt.rules, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHasMajorityWins(t *testing.T) {
+	rules, _ := ScoringRulesFor(enums.StandardRules)
+	tests := []struct {
+		wins, setCount int
+		want           bool
+	}{
+		{1, 1, true},
+		{1, 3, false},
+		{2, 3, true},
+		{2, 5, false},
+		{3, 5, true},
+		{4, 7, true},
+	}
+
+	for _, tt := range tests {
+		if got := rules.HasMajorityWins(tt.wins, tt.setCount); got != tt.want {
+			t.Errorf("HasMajorityWins(%d, %d) = %v, want %v", tt.wins, tt.setCount, got, tt.want)
+		}
+	}
+}
+
+func TestScoringRulesForUnknownName(t *testing.T) {
+	if _, err := ScoringRulesFor("SUDDEN_DEATH"); !errors.Is(err, ErrUnknownScoringRules) {
+		t.Errorf("ScoringRulesFor() error = %v, want %v", err, ErrUnknownScoringRules)
+	}
+	if _, ok := rulesForMatch(db.Match{ScoringRules: ""}).(standardRules); !ok {
+		t.Error("rulesForMatch() without scoring rules does not fall back to the standard rules")
+	}
+}
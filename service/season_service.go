@@ -0,0 +1,380 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrSeasonNotFound = errors.New("season not found")
+var ErrInvalidWeekLength = errors.New("a match week must last at least one day")
+var ErrInvalidWalkoverRule = errors.New("walkover rule must be HOME or HIGHER_PLACED")
+var ErrNotSeasonFixture = errors.New("match is not a season fixture")
+var ErrFixtureDeadlinePassed = errors.New("the deadline of the fixture has passed")
+var ErrOpponentNotInMatch = errors.New("opponent does not play in the match")
+
+type season struct {
+	Id           int
+	Name         string
+	Format       enums.MatchFormat
+	StartsAt     time.Time
+	WeekDays     int
+	WalkoverRule enums.WalkoverRule
+	OpponentIds  []int
+}
+
+// CreateSeason starts a league season between the opponents, in the given
+// order, with one round of the Berger tables per match week. Every fixture
+// must be played by the end of its week, which lasts weekDays days from
+// startsAt onwards. The walkover rule picks the winner of a fixture walked
+// over at its deadline when it cannot be told from who reported ready.
+func (s *service) CreateSeason(
+	name string,
+	format enums.MatchFormat,
+	startsAt time.Time,
+	weekDays int,
+	walkoverRule enums.WalkoverRule,
+	opponentIds []int,
+	settings MatchSettings,
+) (int, error) {
+	var seasonId int
+	err := s.inTx(func(tx *service) error {
+		id, err := tx.createSeason(name, format, startsAt, weekDays, walkoverRule, opponentIds, settings)
+		seasonId = id
+		return err
+	})
+	return seasonId, err
+}
+
+func (s *service) createSeason(
+	name string,
+	format enums.MatchFormat,
+	startsAt time.Time,
+	weekDays int,
+	walkoverRule enums.WalkoverRule,
+	opponentIds []int,
+	settings MatchSettings,
+) (int, error) {
+	if weekDays < 1 {
+		return 0, ErrInvalidWeekLength
+	}
+	if walkoverRule != enums.HomeWins && walkoverRule != enums.HigherPlacedWins {
+		return 0, ErrInvalidWalkoverRule
+	}
+	if len(opponentIds) < 2 {
+		return 0, ErrNotEnoughEntries
+	}
+	seen := make(map[int]bool)
+	for _, id := range opponentIds {
+		if seen[id] {
+			return 0, ErrDuplicateEntry
+		}
+		seen[id] = true
+	}
+
+	settings.EventId = nil
+	template, err := s.newMatch(format, enums.League, settings)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.CreateSeason(&db.Season{
+		Name:         name,
+		Format:       string(format),
+		StartsAt:     startsAt,
+		WeekDays:     weekDays,
+		WalkoverRule: string(walkoverRule),
+	})
+	if err != nil {
+		return 0, err
+	}
+	seasonId := int(id)
+
+	for i, opponentId := range opponentIds {
+		err := s.repo.AddSeasonEntry(&db.SeasonEntry{SeasonId: seasonId, OpponentId: opponentId, Position: i + 1})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i, round := range bergerRounds(len(opponentIds)) {
+		matchWeek := i + 1
+		deadline := startsAt.AddDate(0, 0, matchWeek*weekDays)
+		for _, pairing := range round {
+			match := *template
+			match.SeasonId = &seasonId
+			match.MatchWeek = &matchWeek
+			match.Deadline = &deadline
+			matchId, err := s.repo.CreateMatch(&match)
+			if err != nil {
+				return 0, err
+			}
+			match.Id = int(matchId)
+
+			if err := s.addOpponentToMatch(match, opponentIds[pairing[0]], true); err != nil {
+				return 0, err
+			}
+			if err := s.addOpponentToMatch(match, opponentIds[pairing[1]], false); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return seasonId, nil
+}
+
+func (s *service) GetSeasons() ([]season, error) {
+	seasonsFromDb, err := s.repo.GetAllSeasons()
+	if err != nil {
+		return nil, err
+	}
+
+	seasons := make([]season, 0, len(seasonsFromDb))
+	for _, se := range seasonsFromDb {
+		entries, err := s.repo.GetSeasonEntries(se.Id)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, newSeason(se, entries))
+	}
+	return seasons, nil
+}
+
+func (s *service) GetSeason(seasonId int) (*season, error) {
+	se, err := s.getSeason(seasonId)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetSeasonEntries(seasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newSeason(*se, entries)
+	return &result, nil
+}
+
+// GetSeasonFixtures lists the fixtures of the season by match week.
+func (s *service) GetSeasonFixtures(seasonId int) ([]matchInfo, error) {
+	if _, err := s.getSeason(seasonId); err != nil {
+		return nil, err
+	}
+	matches, err := s.repo.GetMatchesBySeasonId(seasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]matchInfo, 0, len(matches))
+	for _, match := range matches {
+		info, err := s.newMatchInfo(match)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, *info)
+	}
+	return fixtures, nil
+}
+
+// GetLeagueTable ranks the season like a round-robin group, from its finished
+// fixtures only.
+func (s *service) GetLeagueTable(seasonId int) ([]standing, error) {
+	if _, err := s.getSeason(seasonId); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetSeasonEntries(seasonId)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.repo.GetMatchesBySeasonId(seasonId)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string)
+	results := make([]groupResult, 0)
+	for _, match := range matches {
+		opponents, err := s.opponentsFromMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		for _, opp := range opponents {
+			names[opp.Id] = opp.Name
+		}
+		if match.Status != string(enums.Past) {
+			continue
+		}
+
+		result, err := s.groupResultFromMatch(match, opponents)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	opponentIds := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		opponentIds = append(opponentIds, entry.OpponentId)
+	}
+	table := tallyResults(opponentIds, results)
+	ranked := rankByMatchPoints(opponentIds, table, results)
+
+	standings := make([]standing, 0, len(ranked))
+	for i, id := range ranked {
+		st := table[id]
+		st.Position = i + 1
+		st.Name = names[id]
+		standings = append(standings, st)
+	}
+	return standings, nil
+}
+
+// ReportFixtureReady records that the opponent is ready to play the season
+// fixture, which wins the fixture by walkover at its deadline unless the
+// other opponent reported ready too.
+func (s *service) ReportFixtureReady(matchId int, opponentId int, now time.Time) error {
+	return s.inTx(func(tx *service) error {
+		return tx.reportFixtureReady(matchId, opponentId, now)
+	})
+}
+
+func (s *service) reportFixtureReady(matchId int, opponentId int, now time.Time) error {
+	match, err := s.repo.LockMatchById(matchId)
+	if err != nil {
+		return err
+	}
+	if match.SeasonId == nil {
+		return ErrNotSeasonFixture
+	}
+	if match.Status == string(enums.Past) {
+		return ErrMatchAlreadyCompleted
+	}
+	if match.Status != string(enums.Upcoming) {
+		return ErrMatchAlreadyStarted
+	}
+	if match.Deadline != nil && !now.Before(*match.Deadline) {
+		return ErrFixtureDeadlinePassed
+	}
+
+	opponentIds, err := s.opponentIdsFromMatch(*match)
+	if err != nil {
+		return err
+	}
+	if opponentIds[true] != opponentId && opponentIds[false] != opponentId {
+		return ErrOpponentNotInMatch
+	}
+
+	return s.repo.ReportFixtureReady(&db.FixtureReadiness{MatchId: matchId, OpponentId: opponentId})
+}
+
+// ApplySeasonWalkovers closes every season fixture that has not started by
+// its deadline as a walkover. The only opponent that reported ready for the
+// fixture wins it, and otherwise the walkover rule of the season decides.
+// Fixtures already being played are left for the umpire to finish. It
+// returns the ids of the closed fixtures.
+func (s *service) ApplySeasonWalkovers(now time.Time) ([]int, error) {
+	var matchIds []int
+	err := s.inTx(func(tx *service) error {
+		ids, err := tx.applySeasonWalkovers(now)
+		matchIds = ids
+		return err
+	})
+	return matchIds, err
+}
+
+func (s *service) applySeasonWalkovers(now time.Time) ([]int, error) {
+	overdue, err := s.repo.GetOverdueSeasonMatches(now)
+	if err != nil {
+		return nil, err
+	}
+
+	matchIds := make([]int, 0, len(overdue))
+	for _, m := range overdue {
+		match, err := s.repo.LockMatchById(m.Id)
+		if err != nil {
+			return nil, err
+		}
+		if match.Status != string(enums.Upcoming) {
+			continue
+		}
+
+		winnerIsA, err := s.walkoverWinner(*match)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.completeMatch(match, winnerIsA, enums.Walkover); err != nil {
+			return nil, err
+		}
+		matchIds = append(matchIds, match.Id)
+	}
+	return matchIds, nil
+}
+
+// walkoverWinner picks the winner of an overdue fixture: the only opponent
+// that reported ready for it, or else the home side, listed first as
+// opponent A, or the side placed higher in the table, by the walkover rule
+// of the season.
+func (s *service) walkoverWinner(match db.Match) (bool, error) {
+	opponentIds, err := s.opponentIdsFromMatch(match)
+	if err != nil {
+		return false, err
+	}
+	readiness, err := s.repo.GetFixtureReadiness(match.Id)
+	if err != nil {
+		return false, err
+	}
+	ready := make(map[int]bool)
+	for _, r := range readiness {
+		ready[r.OpponentId] = true
+	}
+	if readyA, readyB := ready[opponentIds[true]], ready[opponentIds[false]]; readyA != readyB {
+		return readyA, nil
+	}
+
+	se, err := s.getSeason(*match.SeasonId)
+	if err != nil {
+		return false, err
+	}
+	if enums.WalkoverRule(se.WalkoverRule) != enums.HigherPlacedWins {
+		return true, nil
+	}
+	standings, err := s.GetLeagueTable(se.Id)
+	if err != nil {
+		return false, err
+	}
+	for _, st := range standings {
+		switch st.OpponentId {
+		case opponentIds[true]:
+			return true, nil
+		case opponentIds[false]:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *service) getSeason(id int) (*db.Season, error) {
+	se, err := s.repo.GetSeasonById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSeasonNotFound
+	}
+	return se, err
+}
+
+func newSeason(se db.Season, entries []db.SeasonEntry) season {
+	opponentIds := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		opponentIds = append(opponentIds, entry.OpponentId)
+	}
+	return season{
+		Id:           se.Id,
+		Name:         se.Name,
+		Format:       enums.MatchFormat(se.Format),
+		StartsAt:     se.StartsAt,
+		WeekDays:     se.WeekDays,
+		WalkoverRule: enums.WalkoverRule(se.WalkoverRule),
+		OpponentIds:  opponentIds,
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var seasonDeadline = time.Date(2023, 11, 5, 18, 0, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+
+// newSeasonFixture sets up a singles season between players 1, 2 and 3 with
+// the given fixtures as match id, player A, player B and status, all due at
+// seasonDeadline.
+func newSeasonFixture(rule enums.WalkoverRule, fixtures ...[3]int) (*fakeRepository, *service) {
+	seasonId := 1
+	repo := newFakeRepository()
+	repo.seasons = []db.Season{{Id: seasonId, Format: string(enums.Singles), WalkoverRule: string(rule)}}
+	for position, opponentId := range []int{1, 2, 3} {
+		repo.seasonEntries = append(repo.seasonEntries, db.SeasonEntry{SeasonId: seasonId, OpponentId: opponentId, Position: position + 1})
+	}
+	for _, fixture := range fixtures {
+		repo.matches = append(repo.matches, db.Match{
+			Id:       fixture[0],
+			Format:   string(enums.Singles),
+			Status:   string(enums.Upcoming),
+			SeasonId: &seasonId,
+			Deadline: &seasonDeadline,
+		})
+		repo.lastId = fixture[0]
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: fixture[0], PlayerId: fixture[1], IsOpponentA: true})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: fixture[0], PlayerId: fixture[2], IsOpponentA: false})
+	}
+	return repo, &service{repo: repo}
+}
+
+func TestApplySeasonWalkoversAwardsReadyOpponent(t *testing.T) {
+	repo, svc := newSeasonFixture(enums.HomeWins, [3]int{1, 1, 2}, [3]int{2, 1, 3}, [3]int{3, 2, 3})
+	repo.findMatch(2).Status = string(enums.Ongoing)
+	beforeDeadline := seasonDeadline.Add(-time.Hour)
+
+	if err := svc.ReportFixtureReady(1, 2, beforeDeadline); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ReportFixtureReady(1, 3, beforeDeadline); !errors.Is(err, ErrOpponentNotInMatch) {
+		t.Errorf("ReportFixtureReady() by another player error = %v, want %v", err, ErrOpponentNotInMatch)
+	}
+	if err := svc.ReportFixtureReady(2, 1, beforeDeadline); !errors.Is(err, ErrMatchAlreadyStarted) {
+		t.Errorf("ReportFixtureReady() for a started fixture error = %v, want %v", err, ErrMatchAlreadyStarted)
+	}
+	if err := svc.ReportFixtureReady(3, 2, seasonDeadline); !errors.Is(err, ErrFixtureDeadlinePassed) {
+		t.Errorf("ReportFixtureReady() at the deadline error = %v, want %v", err, ErrFixtureDeadlinePassed)
+	}
+
+	matchIds, err := svc.ApplySeasonWalkovers(seasonDeadline.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(matchIds, want) {
+		t.Errorf("ApplySeasonWalkovers() = %v, want %v", matchIds, want)
+	}
+	for _, matchId := range matchIds {
+		if match := repo.findMatch(matchId); match.Status != string(enums.Past) || match.Result != string(enums.Walkover) {
+			t.Errorf("overdue fixture %d is %s %s, want a walkover", matchId, match.Status, match.Result)
+		}
+	}
+	if match := repo.findMatch(2); match.Status != string(enums.Ongoing) {
+		t.Errorf("fixture being played is %s, want it left ongoing", match.Status)
+	}
+
+	standings, err := svc.GetLeagueTable(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][3]int{1: {1, 0, 1}, 2: {2, 2, 0}, 3: {1, 0, 1}}
+	for _, st := range standings {
+		if got := [3]int{st.Played, st.Won, st.Lost}; got != want[st.OpponentId] {
+			t.Errorf("opponent %d played, won and lost %v, want %v", st.OpponentId, got, want[st.OpponentId])
+		}
+	}
+}
+
+func TestApplySeasonWalkoversToHigherPlacedOpponent(t *testing.T) {
+	repo, svc := newSeasonFixture(enums.HigherPlacedWins, [3]int{1, 1, 2}, [3]int{2, 3, 2})
+	beforeDeadline := seasonDeadline.Add(-time.Hour)
+
+	if _, err := svc.EndMatch(1, enums.Walkover, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, opponentId := range []int{3, 2} {
+		if err := svc.ReportFixtureReady(2, opponentId, beforeDeadline); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.ApplySeasonWalkovers(seasonDeadline.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range repo.players {
+		if row.MatchId == 2 && row.IsWinner != (row.PlayerId == 2) {
+			t.Errorf("player %d is winner %t of the fixture walked over with both ready, want player 2 placed higher to win", row.PlayerId, row.IsWinner)
+		}
+	}
+}
+
+func TestCreateSeasonRejectsUnknownWalkoverRule(t *testing.T) {
+	svc := &service{repo: newFakeRepository()}
+
+	_, err := svc.CreateSeason("Office League", enums.Singles, seasonDeadline, 7, "AWAY", []int{1, 2}, bracketSettings())
+	if !errors.Is(err, ErrInvalidWalkoverRule) {
+		t.Errorf("CreateSeason() error = %v, want %v", err, ErrInvalidWalkoverRule)
+	}
+}
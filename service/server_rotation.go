@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/adarsh-a-tw/tt-backend/db"
+)
+
+// setFirstServerIsA returns whether opponent A serves first in the given set.
+// The first server of the match serves first in every odd numbered set and
+// the opponent serves first in every even numbered set.
+func setFirstServerIsA(match db.Match, setNumber int) bool {
+	return match.FirstServerIsA == (setNumber%2 == 1)
+}
+
+// serverIsA returns whether opponent A serves once the given number of points
+// has been played in a set. Service changes after every two points, and after
+// every point from deuceFromPoint onwards or once the expedite system is in
+// operation.
+func serverIsA(firstServerIsA bool, played int, deuceFromPoint int, expediteFromPoint *int) bool {
+	if expediteFromPoint != nil && played >= *expediteFromPoint {
+		return expediteServerIsA(firstServerIsA, *expediteFromPoint, deuceFromPoint) ==
+			((played-*expediteFromPoint)%2 == 0)
+	}
+
+	changes := played / 2
+	if played >= deuceFromPoint {
+		changes = deuceFromPoint/2 + (played - deuceFromPoint)
+	}
+	return firstServerIsA == (changes%2 == 0)
+}
+
+// expediteServerIsA returns whether opponent A serves the first point under
+// the expedite system. The system is introduced between rallies, so the
+// receiver of the preceding rally serves, or the first server when the set
+// starts expedited.
+func expediteServerIsA(firstServerIsA bool, expediteFromPoint int, deuceFromPoint int) bool {
+	if expediteFromPoint == 0 {
+		return firstServerIsA
+	}
+	return !serverIsA(firstServerIsA, expediteFromPoint-1, deuceFromPoint, nil)
+}
+
+// nextServerIsA returns whether opponent A serves the next point of the set.
+func nextServerIsA(match db.Match, set db.Set) bool {
+	return serverIsA(
+		setFirstServerIsA(match, set.SetNumber),
+		pointsPlayed(match, set),
+		deuceFromPoint(match),
+		set.ExpediteFromPoint,
+	)
+}
+
+// pointsPlayed returns the number of points played in the set, leaving out
+// the handicap the opponents started with.
+func pointsPlayed(match db.Match, set db.Set) int {
+	return set.OpponentAScore + set.OpponentBScore - match.HandicapA - match.HandicapB
+}
+
+// pointsScored returns the points each opponent has scored in the set on top
+// of the handicap they started with.
+func pointsScored(match db.Match, oppAScore int, oppBScore int) (int, int) {
+	return oppAScore - match.HandicapA, oppBScore - match.HandicapB
+}
+
+// deuceFromPoint returns the number of points played after which both
+// opponents can be one point short of the game point.
+func deuceFromPoint(match db.Match) int {
+	point := 2*(match.GamePoint-1) - match.HandicapA - match.HandicapB
+	if point < 0 {
+		return 0
+	}
+	return point
+}
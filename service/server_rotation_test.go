@@ -0,0 +1,31 @@
+package service
+
+import "testing"
+
+func TestServerIsA(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name              string
+		played            int
+		expediteFromPoint *int
+		want              bool
+	}{
+		{"first two points", 1, nil, true},
+		{"second two points", 2, nil, false},
+		{"deuce alternates every point", 21, nil, false},
+		{"expedite from the start of the set", 0, intPtr(0), true},
+		{"expedite alternates every point", 1, intPtr(0), false},
+		{"expedite after an even count keeps the rotation", 4, intPtr(4), true},
+		{"expedite after an odd count goes to the previous receiver", 5, intPtr(5), false},
+		{"expedite alternates from the previous receiver", 6, intPtr(5), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serverIsA(true, tt.played, 20, tt.expediteFromPoint); got != tt.want {
+				t.Errorf("serverIsA(true, %d, 20, %v) = %v, want %v", tt.played, tt.expediteFromPoint, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,127 @@
 package service
 
 import (
+	"errors"
+	"sort"
+	"time"
+
 	"github.com/adarsh-a-tw/tt-backend/db"
 	"github.com/adarsh-a-tw/tt-backend/enums"
 )
 
 type Service interface {
-	CreateDoublesMatch(stage enums.MatchStage, teamAId int, teamBId int, maxSets int, gamePoint int) error
-	CreatePlayer(name string) error
-	CreateSinglesMatch(stage enums.MatchStage, playerAId int, playerBId int, maxSets int, gamePoint int) error
-	CreateTeam(playerAName string, playerBName string) error
-	CreateSet(matchId int) error
-	GetMatchInfoList(status string) ([]matchInfo, error)
-	UpdateScore(matchId int, setId int, scoredByA bool) error
-	UndoScoreUpdate(matchId int, setId int) error
+	CreateDoublesMatch(stage enums.MatchStage, teamAId int, teamBId int, settings MatchSettings) error
+	CreatePlayer(profile PlayerProfile, tournamentId *int) (int, error)
+	GetPlayers(name string, tournamentId *int) ([]Player, error)
+	GetPlayer(playerId int) (*Player, error)
+	UpdatePlayer(playerId int, profile PlayerProfile) error
+	DeletePlayer(playerId int) error
+	GetRankings(format enums.MatchFormat) ([]ranking, error)
+	GetPlayerRatingHistory(playerId int) ([]ratingChange, error)
+	CreateSinglesMatch(stage enums.MatchStage, playerAId int, playerBId int, settings MatchSettings) error
+	CreateTeam(playerAId int, playerBId int, tournamentId *int) error
+	FindPlayerId(name string, tournamentId *int) (int, error)
+	CreateSet(matchId int, firstServerIsA *bool) error
+	GetMatchInfoList(status string, tournamentId *int, eventId *int) ([]matchInfo, error)
+	UpdateScore(matchId int, setId int, scoredByA bool, autoNextSet bool, expectedVersion *int) ([]int, error)
+	UndoScoreUpdate(matchId int, setId int) ([]int, error)
+	Undo(matchId int) ([]int, error)
+	Redo(matchId int) ([]int, error)
+	CorrectSetScore(matchId int, setId int, oppAScore int, oppBScore int, reason string) ([]int, error)
+	StartExpedite(matchId int, setId int) error
+	AwardExpediteReturnPoint(matchId int, setId int) ([]int, error)
+	SetDoublesServiceOrder(matchId int, setId int, firstServerIsPlayerA bool, firstReceiverIsPlayerA *bool) error
 	GetMatchDetails(matchId int) (*MatchDetail, error)
+	EndMatch(matchId int, result enums.MatchResult, winnerIsA bool) ([]int, error)
+	RecordMatchEvent(matchId int, eventType enums.MatchEventType, isOppA *bool, detail string) error
+	CreateMatchFormatTemplate(name string, setCount int, gamePoint int, scoringRules enums.ScoringRuleSet) error
+	GetMatchFormatTemplates() ([]matchFormatTemplate, error)
+	GetMatchFormatTemplate(name string) (*matchFormatTemplate, error)
+	UpdateMatchFormatTemplate(name string, setCount int, gamePoint int, scoringRules enums.ScoringRuleSet) error
+	DeleteMatchFormatTemplate(name string) error
+	CreateTournament(name string) (int, error)
+	GetTournaments() ([]tournament, error)
+	CreateEvent(tournamentId int, name string, format enums.MatchFormat) (int, error)
+	GetEvents(tournamentId int) ([]event, error)
+	GenerateKnockoutBracket(eventId int, entries []BracketEntry, drawType enums.DrawType, settings MatchSettings) error
+	CreateGroup(eventId int, name string, opponentIds []int, settings MatchSettings) (int, error)
+	GetGroups(eventId int) ([]group, error)
+	GetGroupStandings(groupId int) ([]standing, error)
+	AddMatchToGroup(groupId int, matchId int) error
+	QualifyFromGroups(eventId int, qualifiersPerGroup int, settings MatchSettings) error
+	CreateTie(eventId *int, stage enums.MatchStage, sideA string, sideB string, order []string, lineupA map[string]int, lineupB map[string]int, settings MatchSettings) (int, error)
+	GetTie(tieId int) (*tieDetail, error)
+	AddEventEntries(eventId int, entries []BracketEntry) error
+	GenerateSwissRound(eventId int, settings MatchSettings) (int, error)
+	GetSwissStandings(eventId int) ([]swissStanding, error)
+	GetBracketTree(eventId int, bracket enums.BracketType) (*BracketNode, error)
+	HoldDraw(eventId int, entries []BracketEntry, randomSeed *int64, drawType enums.DrawType, settings MatchSettings) (*drawRecord, error)
+	GetDraw(eventId int) (*drawRecord, error)
+	CommitDrawSeed(eventId int, seedHash string, committedBy string) (*drawCommitment, error)
+	GetDrawCommitment(eventId int) (*drawCommitment, error)
+	VerifyDraw(eventId int) (*drawVerification, error)
+	CreateSeason(name string, format enums.MatchFormat, startsAt time.Time, weekDays int, walkoverRule enums.WalkoverRule, opponentIds []int, settings MatchSettings) (int, error)
+	GetSeasons() ([]season, error)
+	GetSeason(seasonId int) (*season, error)
+	GetSeasonFixtures(seasonId int) ([]matchInfo, error)
+	GetLeagueTable(seasonId int) ([]standing, error)
+	ReportFixtureReady(matchId int, opponentId int, now time.Time) error
+	ApplySeasonWalkovers(now time.Time) ([]int, error)
 }
 
+var ErrConcurrentUpdate = errors.New("match was updated concurrently")
+
 type service struct {
 	repo db.Repository
+
+	// changed collects the matches changed as a side effect of the change
+	// being made within inTxChanging.
+	changed map[int]bool
 }
 
 func NewService(repo db.Repository) Service {
 	return &service{repo: repo}
 }
+
+// inTx runs fn with a copy of the service whose repository is bound to a
+// single transaction.
+func (s *service) inTx(fn func(tx *service) error) error {
+	err := s.repo.RunInTx(func(repo db.Repository) error {
+		tx := *s
+		tx.repo = repo
+		return fn(&tx)
+	})
+	if errors.Is(err, db.ErrVersionConflict) {
+		return ErrConcurrentUpdate
+	}
+	return err
+}
+
+// inTxChanging runs fn like inTx and returns the ids of the other matches it
+// changed on the way, such as the next match of a bracket, so that their
+// subscribers can be notified as well.
+func (s *service) inTxChanging(fn func(tx *service) error) ([]int, error) {
+	changed := make(map[int]bool)
+	err := s.inTx(func(tx *service) error {
+		tx.changed = changed
+		return fn(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matchIds := make([]int, 0, len(changed))
+	for matchId := range changed {
+		matchIds = append(matchIds, matchId)
+	}
+	sort.Ints(matchIds)
+	return matchIds, nil
+}
+
+// markChanged records a match changed as a side effect of the change being
+// made.
+func (s *service) markChanged(matchId int) {
+	if s.changed != nil {
+		s.changed[matchId] = true
+	}
+}
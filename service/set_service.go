@@ -2,7 +2,7 @@ package service
 
 import (
 	"errors"
-	"math"
+	"time"
 
 	"github.com/adarsh-a-tw/tt-backend/db"
 	"github.com/adarsh-a-tw/tt-backend/enums"
@@ -13,10 +13,16 @@ var ErrPreviousSetNotCompleted = errors.New("previous set not completed")
 var ErrSetNotFound = errors.New("set not found")
 var ErrSetAlreadyCompleted = errors.New("set already completed")
 var ErrNoScoreToUndo = errors.New("no score to undo")
+var ErrOpponentsNotDecided = errors.New("opponents of the match are not decided yet")
 
-func (s *service) CreateSet(matchId int) error {
+func (s *service) CreateSet(matchId int, firstServerIsA *bool) error {
+	return s.inTx(func(tx *service) error {
+		return tx.createSet(matchId, firstServerIsA)
+	})
+}
 
-	match, err := s.repo.GetMatchById(matchId)
+func (s *service) createSet(matchId int, firstServerIsA *bool) error {
+	match, err := s.repo.LockMatchById(matchId)
 	if err != nil {
 		return err
 	}
@@ -34,22 +40,58 @@ func (s *service) CreateSet(matchId int) error {
 		}
 	}
 
+	if len(existing_sets) == 0 {
+		opponentIds, err := s.opponentIdsFromMatch(*match)
+		if err != nil {
+			return err
+		}
+		if len(opponentIds) < 2 {
+			return ErrOpponentsNotDecided
+		}
+	}
+
+	if len(existing_sets) == 0 && firstServerIsA != nil {
+		err = s.repo.UpdateMatchFirstServer(matchId, *firstServerIsA)
+		if err != nil {
+			return err
+		}
+	}
+
 	set := db.Set{
-		SetNumber: len(existing_sets) + 1,
-		MatchId:   matchId,
+		SetNumber:      len(existing_sets) + 1,
+		MatchId:        matchId,
+		OpponentAScore: match.HandicapA,
+		OpponentBScore: match.HandicapB,
+		StartedAt:      time.Now(),
+	}
+	if isExpedited(existing_sets) {
+		set.ExpediteFromPoint = new(int)
 	}
 
 	_, err = s.repo.CreateSet(&set)
+	if err != nil {
+		return err
+	}
 
 	if match.Status == string(enums.Upcoming) {
 		err = s.repo.UpdateMatchStatus(matchId, string(enums.Ongoing))
+		if err != nil {
+			return err
+		}
+		return s.refreshTie(match)
 	}
 
-	return err
+	return nil
 }
 
-func (s *service) UndoScoreUpdate(matchId int, setId int) error {
-	match, err := s.repo.GetMatchById(matchId)
+func (s *service) UndoScoreUpdate(matchId int, setId int) ([]int, error) {
+	return s.inTxChanging(func(tx *service) error {
+		return tx.undoScoreUpdate(matchId, setId)
+	})
+}
+
+func (s *service) undoScoreUpdate(matchId int, setId int) error {
+	match, err := s.repo.LockMatchById(matchId)
 	if err != nil {
 		return err
 	}
@@ -64,70 +106,74 @@ func (s *service) UndoScoreUpdate(matchId int, setId int) error {
 	if latestSet.Id != setId {
 		return ErrSetNotFound
 	}
-	limit := 2
-	setLogs, err := s.repo.GetSetLogsBySetId(latestSet.Id, &limit)
-	if err != nil {
-		return err
-	}
-	if len(setLogs) == 0 {
-		return ErrNoScoreToUndo
-	}
-	err = s.repo.DeleteSetLog(setLogs[0].Id)
-	if err != nil {
-		return err
-	}
-	if len(setLogs) == 1 {
-		latestSet.OpponentAScore = 0
-		latestSet.OpponentBScore = 0
-	} else {
-		latestSet.OpponentAScore = setLogs[1].OppAScore
-		latestSet.OpponentBScore = setLogs[1].OppBScore
-	}
-	latestSet.IsCompleted = false
-	err = s.repo.UpdateSet(&latestSet)
-	if err != nil {
-		return err
-	}
-	if match.Status == string(enums.Past) {
-		err = s.repo.UpdateMatchStatus(match.Id, string(enums.Ongoing))
-		if err != nil {
-			return err
-		}
-		return s.repo.ResetMatchWinner(match)
-	}
-	return nil
+	return s.undoLastPoint(match, existing_sets)
 }
 
 func (s *service) UpdateScore(
 	matchId int,
 	setId int,
 	scoredByA bool,
-) error {
-	match, err := s.repo.GetMatchById(matchId)
+	autoNextSet bool,
+	expectedVersion *int,
+) ([]int, error) {
+	return s.inTxChanging(func(tx *service) error {
+		match, set, err := tx.getOpenSet(matchId, setId)
+		if err != nil {
+			return err
+		}
+		if expectedVersion != nil && *expectedVersion != set.Version {
+			return ErrConcurrentUpdate
+		}
+		err = tx.repo.ClearScoreRedos(matchId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.scorePoint(match, set, scoredByA)
+		if err != nil {
+			return err
+		}
+
+		return tx.handleChangeOfEnds(match, set, autoNextSet)
+	})
+}
+
+// getOpenSet locks the match and returns it with the given set, which must
+// still be in progress.
+func (s *service) getOpenSet(matchId int, setId int) (*db.Match, *db.Set, error) {
+	match, err := s.repo.LockMatchById(matchId)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	existing_sets, err := s.repo.GetSetsByMatchId(matchId)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	if match.Status == string(enums.Past) {
-		return ErrGameOverOrSetCountExceeded
+		return nil, nil, ErrGameOverOrSetCountExceeded
 	}
 	var set *db.Set
-	for _, s := range existing_sets {
-		if s.Id == setId {
-			set = &s
+	for i := range existing_sets {
+		if existing_sets[i].Id == setId {
+			set = &existing_sets[i]
 		}
 	}
 	if set == nil {
-		return ErrSetNotFound
+		return nil, nil, ErrSetNotFound
 	}
 
 	if set.IsCompleted {
-		return ErrSetAlreadyCompleted
+		return nil, nil, ErrSetAlreadyCompleted
 	}
 
+	return match, set, nil
+}
+
+func (s *service) scorePoint(match *db.Match, set *db.Set, scoredByA bool) error {
+	startExpediteIfOverdue(*match, set, time.Now())
+
+	servedByA := nextServerIsA(*match, *set)
+
 	if scoredByA {
 		set.OpponentAScore += 1
 	} else {
@@ -139,15 +185,16 @@ func (s *service) UpdateScore(
 		OppAScore: set.OpponentAScore,
 		OppBScore: set.OpponentBScore,
 		ScoredByA: scoredByA,
+		ServedByA: servedByA,
 	}
 
-	err = s.repo.CreateSetLog(setLog)
+	err := s.repo.CreateSetLog(setLog)
 
 	if err != nil {
 		return err
 	}
 
-	set.IsCompleted = isSetComplete(*set, match.GamePoint)
+	set.IsCompleted = rulesForMatch(*match).IsSetComplete(*set, match.GamePoint)
 
 	err = s.repo.UpdateSet(set)
 
@@ -156,10 +203,10 @@ func (s *service) UpdateScore(
 	}
 
 	if set.IsCompleted {
-		s.handleMatchCompletion(match)
+		return s.handleMatchCompletion(match)
 	}
 
-	return err
+	return nil
 }
 
 func (s *service) handleMatchCompletion(match *db.Match) error {
@@ -168,9 +215,24 @@ func (s *service) handleMatchCompletion(match *db.Match) error {
 		return err
 	}
 
+	winnerIsA, decided := matchWinner(*match, existing_sets)
+	if decided {
+		return s.completeMatch(match, winnerIsA, enums.Completed)
+	}
+
+	return nil
+}
+
+// matchWinner reports whether the finished sets decide the match and, if so,
+// whether opponent A won it.
+func matchWinner(match db.Match, sets []db.Set) (winnerIsA bool, decided bool) {
+	rules := rulesForMatch(match)
 	wonByA := 0
 	wonByB := 0
-	for _, set := range existing_sets {
+	for _, set := range sets {
+		if !set.IsCompleted {
+			continue
+		}
 		if set.OpponentAScore > set.OpponentBScore {
 			wonByA += 1
 		} else {
@@ -178,32 +240,11 @@ func (s *service) handleMatchCompletion(match *db.Match) error {
 		}
 	}
 
-	if hasMajorityWins(wonByA, match.SetCount) {
-		err = s.repo.UpdateMatchWinner(match, true)
-		if err != nil {
-			return err
-		}
-		return s.repo.UpdateMatchStatus(match.Id, string(enums.Past))
+	if rules.HasMajorityWins(wonByA, match.SetCount) {
+		return true, true
 	}
-
-	if hasMajorityWins(wonByB, match.SetCount) {
-		err = s.repo.UpdateMatchWinner(match, false)
-		if err != nil {
-			return err
-		}
-		return s.repo.UpdateMatchStatus(match.Id, string(enums.Past))
+	if rules.HasMajorityWins(wonByB, match.SetCount) {
+		return false, true
 	}
-
-	return nil
-}
-
-func hasMajorityWins(wins int, setCount int) bool {
-	return (wins*100)/setCount > 50
-}
-
-func isSetComplete(set db.Set, gamePoint int) bool {
-	scoreDiff := int(math.Abs(float64(set.OpponentAScore - set.OpponentBScore)))
-	maxScore := int(math.Max(float64(set.OpponentAScore), float64(set.OpponentBScore)))
-
-	return maxScore >= gamePoint && scoreDiff > 1
+	return false, false
 }
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateScoreRejectsStaleVersion(t *testing.T) {
+	repo, svc := newScoringFixture(t, 3)
+	setId := repo.sets[0].Id
+	version := repo.sets[0].Version
+	if _, err := svc.UpdateScore(1, setId, true, false, &version); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.UpdateScore(1, setId, false, false, &version); !errors.Is(err, ErrConcurrentUpdate) {
+		t.Fatalf("UpdateScore() with version %d error = %v, want %v", version, err, ErrConcurrentUpdate)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 1 || set.OpponentBScore != 0 || set.Version != version+1 {
+		t.Errorf("set is %d-%d at version %d, want 1-0 at version %d", set.OpponentAScore, set.OpponentBScore, set.Version, version+1)
+	}
+	if len(repo.setLogs) != 1 {
+		t.Errorf("%d points logged, want 1", len(repo.setLogs))
+	}
+}
+
+func TestVersionConflictOnWriteIsConcurrentUpdate(t *testing.T) {
+	repo, svc := newScoringFixture(t, 3)
+	setId := repo.sets[0].Id
+
+	// Another request scores after this one read the set.
+	err := svc.inTx(func(tx *service) error {
+		_, set, err := tx.getOpenSet(1, setId)
+		if err != nil {
+			return err
+		}
+		repo.sets[0].Version += 1
+		return tx.repo.UpdateSet(set)
+	})
+	if !errors.Is(err, ErrConcurrentUpdate) {
+		t.Errorf("inTx() error = %v, want %v", err, ErrConcurrentUpdate)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+type standing struct {
+	Position    int
+	OpponentId  int
+	Name        string
+	Played      int
+	Won         int
+	Lost        int
+	MatchPoints int
+	GamesWon    int
+	GamesLost   int
+	PointsWon   int
+	PointsLost  int
+}
+
+// groupResult is a finished group match between opponents A and B.
+type groupResult struct {
+	OppAId    int
+	OppBId    int
+	WinnerIsA bool
+	ByDefault bool
+	GamesA    int
+	GamesB    int
+	PointsA   int
+	PointsB   int
+}
+
+// GetGroupStandings ranks the group by the ITTF rules. A win earns 2 match
+// points, a loss 1, and a match lost by walkover, retirement or
+// disqualification none. Opponents level on match points are separated by
+// the match points, then the games ratio and then the points ratio of the
+// matches played between them only. Opponents still level keep their draw
+// order. Only finished matches are counted.
+func (s *service) GetGroupStandings(groupId int) ([]standing, error) {
+	if _, err := s.getGroup(groupId); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetMatchGroupEntries(groupId)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.repo.GetMatchesByGroupId(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string)
+	results := make([]groupResult, 0)
+	for _, match := range matches {
+		opponents, err := s.opponentsFromMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		for _, opp := range opponents {
+			names[opp.Id] = opp.Name
+		}
+		if match.Status != string(enums.Past) {
+			continue
+		}
+
+		result, err := s.groupResultFromMatch(match, opponents)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	opponentIds := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		opponentIds = append(opponentIds, entry.OpponentId)
+	}
+	table := tallyResults(opponentIds, results)
+	ranked := rankByMatchPoints(opponentIds, table, results)
+
+	standings := make([]standing, 0, len(ranked))
+	for i, id := range ranked {
+		st := table[id]
+		st.Position = i + 1
+		st.Name = names[id]
+		standings = append(standings, st)
+	}
+	return standings, nil
+}
+
+func (s *service) groupResultFromMatch(match db.Match, opponents []opponent) (*groupResult, error) {
+	sets, err := s.repo.GetSetsByMatchId(match.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &groupResult{
+		OppAId:    opponents[0].Id,
+		OppBId:    opponents[1].Id,
+		WinnerIsA: opponents[0].IsWinner,
+		ByDefault: match.Result != string(enums.Completed),
+	}
+	for _, set := range sets {
+		if set.IsCompleted {
+			if set.OpponentAScore > set.OpponentBScore {
+				result.GamesA += 1
+			} else {
+				result.GamesB += 1
+			}
+		}
+		result.PointsA += set.OpponentAScore
+		result.PointsB += set.OpponentBScore
+	}
+	return result, nil
+}
+
+// tallyResults adds up the results of the given opponents, leaving out
+// matches against anyone else.
+func tallyResults(opponentIds []int, results []groupResult) map[int]standing {
+	table := make(map[int]standing)
+	for _, id := range opponentIds {
+		table[id] = standing{OpponentId: id}
+	}
+
+	for _, r := range results {
+		a, okA := table[r.OppAId]
+		b, okB := table[r.OppBId]
+		if !okA || !okB {
+			continue
+		}
+
+		a.Played += 1
+		b.Played += 1
+		a.GamesWon += r.GamesA
+		a.GamesLost += r.GamesB
+		b.GamesWon += r.GamesB
+		b.GamesLost += r.GamesA
+		a.PointsWon += r.PointsA
+		a.PointsLost += r.PointsB
+		b.PointsWon += r.PointsB
+		b.PointsLost += r.PointsA
+
+		winner, loser := &a, &b
+		if !r.WinnerIsA {
+			winner, loser = &b, &a
+		}
+		winner.Won += 1
+		winner.MatchPoints += 2
+		loser.Lost += 1
+		if !r.ByDefault {
+			loser.MatchPoints += 1
+		}
+
+		table[r.OppAId] = a
+		table[r.OppBId] = b
+	}
+	return table
+}
+
+// rankByMatchPoints orders the opponents by match points in table and breaks
+// ties between them with the results of their matches against each other.
+func rankByMatchPoints(opponentIds []int, table map[int]standing, results []groupResult) []int {
+	ranked := append([]int{}, opponentIds...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return table[ranked[i]].MatchPoints > table[ranked[j]].MatchPoints
+	})
+
+	same := func(a, b int) bool { return table[a].MatchPoints == table[b].MatchPoints }
+	return splitTies(ranked, same, func(tied []int) []int {
+		return breakTie(tied, results)
+	})
+}
+
+// breakTie orders opponents level on match points using only the matches
+// between them. Each criterion splits the opponents into smaller tied groups,
+// which start again from match points among themselves.
+func breakTie(tied []int, results []groupResult) []int {
+	table := tallyResults(tied, results)
+	criteria := []func(a, b standing) int{
+		func(a, b standing) int { return a.MatchPoints - b.MatchPoints },
+		func(a, b standing) int { return compareRatios(a.GamesWon, a.GamesLost, b.GamesWon, b.GamesLost) },
+		func(a, b standing) int { return compareRatios(a.PointsWon, a.PointsLost, b.PointsWon, b.PointsLost) },
+	}
+
+	for _, compare := range criteria {
+		ordered := append([]int{}, tied...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return compare(table[ordered[i]], table[ordered[j]]) > 0
+		})
+
+		if compare(table[ordered[0]], table[ordered[len(ordered)-1]]) == 0 {
+			continue
+		}
+
+		same := func(a, b int) bool { return compare(table[a], table[b]) == 0 }
+		return splitTies(ordered, same, func(subgroup []int) []int {
+			return breakTie(subgroup, results)
+		})
+	}
+	return tied
+}
+
+// splitTies passes every run of opponents that are the same to resolve, and
+// returns the ordering with each run replaced by its resolved order.
+func splitTies(ordered []int, same func(a, b int) bool, resolve func(tied []int) []int) []int {
+	result := make([]int, 0, len(ordered))
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && same(ordered[start], ordered[end]) {
+			end += 1
+		}
+		if end-start > 1 {
+			result = append(result, resolve(ordered[start:end])...)
+		} else {
+			result = append(result, ordered[start])
+		}
+		start = end
+	}
+	return result
+}
+
+// compareRatios compares wonA/lostA with wonB/lostB without dividing. A ratio
+// with nothing lost is higher than any other, and 0/0 counts as 0.
+func compareRatios(wonA, lostA, wonB, lostB int) int {
+	infA := lostA == 0 && wonA > 0
+	infB := lostB == 0 && wonB > 0
+	switch {
+	case infA && infB:
+		return 0
+	case infA:
+		return 1
+	case infB:
+		return -1
+	}
+	if lostA == 0 {
+		wonA, lostA = 0, 1
+	}
+	if lostB == 0 {
+		wonB, lostB = 0, 1
+	}
+	return wonA*lostB - wonB*lostA
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankByMatchPoints(t *testing.T) {
+	const a, b, c, d = 1, 2, 3, 4
+	win := func(winner, loser, gamesW, gamesL, pointsW, pointsL int) groupResult {
+		return groupResult{
+			OppAId:    winner,
+			OppBId:    loser,
+			WinnerIsA: true,
+			GamesA:    gamesW,
+			GamesB:    gamesL,
+			PointsA:   pointsW,
+			PointsB:   pointsL,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		opponentIds []int
+		results     []groupResult
+		want        []int
+	}{
+		{
+			name:        "three-way tie on match points split by games between them only",
+			opponentIds: []int{a, b, c, d},
+			results: []groupResult{
+				win(a, b, 3, 0, 33, 20),
+				win(b, c, 3, 1, 40, 30),
+				win(c, a, 3, 2, 50, 45),
+				win(a, d, 3, 2, 50, 45),
+				win(b, d, 3, 0, 33, 10),
+				win(c, d, 3, 2, 50, 48),
+			},
+			want: []int{a, c, b, d},
+		},
+		{
+			name:        "three-way tie on match points and games split by points",
+			opponentIds: []int{a, b, c},
+			results: []groupResult{
+				win(a, b, 3, 1, 40, 30),
+				win(b, c, 3, 1, 40, 35),
+				win(c, a, 3, 1, 38, 36),
+			},
+			want: []int{a, c, b},
+		},
+		{
+			name:        "pair left level on games starts again from match points",
+			opponentIds: []int{a, b, c},
+			results: []groupResult{
+				win(a, b, 4, 0, 44, 20),
+				win(b, c, 3, 0, 33, 25),
+				win(c, a, 3, 1, 40, 35),
+			},
+			want: []int{a, b, c},
+		},
+		{
+			name:        "level on everything keeps the draw order",
+			opponentIds: []int{c, a, b},
+			results: []groupResult{
+				win(a, b, 3, 0, 33, 0),
+				win(b, c, 3, 0, 33, 0),
+				win(c, a, 3, 0, 33, 0),
+			},
+			want: []int{c, a, b},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := tallyResults(tt.opponentIds, tt.results)
+			if got := rankByMatchPoints(tt.opponentIds, table, tt.results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankByMatchPoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareRatios(t *testing.T) {
+	tests := []struct {
+		wonA, lostA, wonB, lostB int
+		want                     int
+	}{
+		{3, 0, 5, 1, 1},
+		{5, 1, 3, 0, -1},
+		{3, 0, 1, 0, 0},
+		{0, 0, 0, 1, 0},
+		{2, 4, 1, 2, 0},
+		{1, 3, 1, 2, -1},
+	}
+
+	for _, tt := range tests {
+		got := compareRatios(tt.wonA, tt.lostA, tt.wonB, tt.lostB)
+		if sign(got) != tt.want {
+			t.Errorf("compareRatios(%d, %d, %d, %d) = %d, want sign %d", tt.wonA, tt.lostA, tt.wonB, tt.lostB, got, tt.want)
+		}
+	}
+}
+
+func TestBergerRounds(t *testing.T) {
+	tests := []struct {
+		count int
+		want  [][][2]int
+	}{
+		{3, [][][2]int{{{1, 2}}, {{2, 0}}, {{0, 1}}}},
+		{4, [][][2]int{{{0, 3}, {1, 2}}, {{3, 1}, {2, 0}}, {{2, 3}, {0, 1}}}},
+	}
+	for _, tt := range tests {
+		if got := bergerRounds(tt.count); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("bergerRounds(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+
+	for count := 2; count <= 9; count++ {
+		rounds := bergerRounds(count)
+		wantRounds := count - 1
+		if count%2 == 1 {
+			wantRounds = count
+		}
+		if len(rounds) != wantRounds {
+			t.Errorf("bergerRounds(%d) has %d rounds, want %d", count, len(rounds), wantRounds)
+		}
+
+		met := make(map[[2]int]int)
+		sitOut := make(map[int]int)
+		for r, round := range rounds {
+			playing := make(map[int]bool)
+			for _, pair := range round {
+				for _, entry := range pair {
+					if playing[entry] {
+						t.Errorf("bergerRounds(%d) round %d: entry %d plays twice", count, r, entry)
+					}
+					playing[entry] = true
+				}
+				key := pair
+				if key[0] > key[1] {
+					key[0], key[1] = key[1], key[0]
+				}
+				met[key] += 1
+			}
+			for entry := 0; entry < count; entry++ {
+				if !playing[entry] {
+					sitOut[entry] += 1
+				}
+			}
+		}
+
+		for i := 0; i < count; i++ {
+			for j := i + 1; j < count; j++ {
+				if met[[2]int{i, j}] != 1 {
+					t.Errorf("bergerRounds(%d): %d and %d meet %d times", count, i, j, met[[2]int{i, j}])
+				}
+			}
+			wantSitOut := 0
+			if count%2 == 1 {
+				wantSitOut = 1
+			}
+			if sitOut[i] != wantSitOut {
+				t.Errorf("bergerRounds(%d): entry %d sits out %d rounds, want %d", count, i, sitOut[i], wantSitOut)
+			}
+		}
+	}
+}
+
+func sign(i int) int {
+	switch {
+	case i > 0:
+		return 1
+	case i < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrSwissRoundNotFinished = errors.New("previous swiss round not finished")
+var ErrNoSwissPairing = errors.New("no pairing left without a rematch")
+var ErrSwissRoundsExhausted = errors.New("every entry has already played every other entry")
+
+type swissStanding struct {
+	Position   int
+	OpponentId int
+	Name       string
+	Played     int
+	Won        int
+	Lost       int
+	Byes       int
+	Score      int
+	Buchholz   int
+}
+
+// swissState is the result of every swiss round of an event so far.
+type swissState struct {
+	Round      int
+	Finished   bool
+	Entries    []db.EventEntry
+	Standings  map[int]*swissStanding
+	Opponents  map[int][]int
+	HadBye     map[int]bool
+	Rematches  map[[2]int]bool
+	EntryIndex map[int]int
+}
+
+// AddEventEntries enters players or teams into the event, or updates their
+// seed when they were already entered. Singles events take players and
+// doubles events teams, registered for the tournament of the event or for no
+// tournament.
+func (s *service) AddEventEntries(eventId int, entries []BracketEntry) error {
+	return s.inTx(func(tx *service) error {
+		return tx.addEventEntries(eventId, entries)
+	})
+}
+
+func (s *service) addEventEntries(eventId int, entries []BracketEntry) error {
+	event, err := s.getEvent(eventId)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	for _, entry := range entries {
+		if seen[entry.OpponentId] {
+			return ErrDuplicateEntry
+		}
+		seen[entry.OpponentId] = true
+		if err := s.checkEntryInEvent(*event, entry.OpponentId); err != nil {
+			return err
+		}
+
+		seed := entry.Seed
+		if seed < 0 {
+			seed = 0
+		}
+		err := s.repo.AddEventEntry(&db.EventEntry{EventId: eventId, OpponentId: entry.OpponentId, Seed: seed})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEntryInEvent checks that the opponent exists in the format of the
+// event and is not registered for another tournament.
+func (s *service) checkEntryInEvent(event db.Event, opponentId int) error {
+	var tournamentId *int
+	switch enums.MatchFormat(event.Format) {
+	case enums.Singles:
+		player, err := s.getPlayer(opponentId)
+		if err != nil {
+			return err
+		}
+		tournamentId = player.TournamentId
+	case enums.Doubles:
+		team, err := s.getTeam(opponentId)
+		if err != nil {
+			return err
+		}
+		tournamentId = team.TournamentId
+	default:
+		return ErrTeamEventNeedsTies
+	}
+
+	if tournamentId != nil && *tournamentId != event.TournamentId {
+		return ErrOpponentNotInTournament
+	}
+	return nil
+}
+
+// GenerateSwissRound pairs the entries of the event for the next swiss round
+// once every match of the current round is over, and returns the number of
+// the new round. Entries are paired from the top of the standings down with
+// the highest ranked opponent on the same or the nearest score they have not
+// played yet. With an odd number of entries the lowest ranked entry without
+// a bye so far gets one, worth a win. Once every entry could have played
+// every other no further round is paired.
+func (s *service) GenerateSwissRound(eventId int, settings MatchSettings) (int, error) {
+	var round int
+	err := s.inTx(func(tx *service) error {
+		r, err := tx.generateSwissRound(eventId, settings)
+		round = r
+		return err
+	})
+	return round, err
+}
+
+func (s *service) generateSwissRound(eventId int, settings MatchSettings) (int, error) {
+	event, err := s.getEvent(eventId)
+	if err != nil {
+		return 0, err
+	}
+	state, err := s.swissState(eventId)
+	if err != nil {
+		return 0, err
+	}
+	if !state.Finished {
+		return 0, ErrSwissRoundNotFinished
+	}
+	if len(state.Entries) < 2 {
+		return 0, ErrNotEnoughEntries
+	}
+	if state.Round >= maxSwissRounds(len(state.Entries)) {
+		return 0, ErrSwissRoundsExhausted
+	}
+
+	settings.EventId = &eventId
+	format := enums.MatchFormat(event.Format)
+	template, err := s.newMatch(format, enums.Prelims, settings)
+	if err != nil {
+		return 0, err
+	}
+
+	ranked := state.ranked(false)
+	pairer := newSwissPairer(state.Rematches)
+	byeId := 0
+	var pairs [][2]int
+	if len(ranked)%2 == 1 {
+		for i := len(ranked) - 1; i >= 0 && byeId == 0; i-- {
+			if state.HadBye[ranked[i]] {
+				continue
+			}
+			rest := append(append([]int{}, ranked[:i]...), ranked[i+1:]...)
+			if p, ok := pairer.pair(rest); ok {
+				byeId = ranked[i]
+				pairs = p
+			}
+		}
+		if byeId == 0 {
+			return 0, ErrNoSwissPairing
+		}
+	} else {
+		p, ok := pairer.pair(ranked)
+		if !ok {
+			return 0, ErrNoSwissPairing
+		}
+		pairs = p
+	}
+
+	swissRound := state.Round + 1
+	for _, pair := range pairs {
+		match := *template
+		match.SwissRound = &swissRound
+		matchId, err := s.repo.CreateMatch(&match)
+		if err != nil {
+			return 0, err
+		}
+		match.Id = int(matchId)
+
+		if err := s.addOpponentToMatch(match, pair[0], true); err != nil {
+			return 0, err
+		}
+		if err := s.addOpponentToMatch(match, pair[1], false); err != nil {
+			return 0, err
+		}
+		if err := s.checkOpponentsInEvent(match.Id, format, &eventId); err != nil {
+			return 0, err
+		}
+	}
+
+	if byeId != 0 {
+		err := s.repo.CreateSwissBye(&db.SwissBye{EventId: eventId, Round: swissRound, OpponentId: byeId})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return swissRound, nil
+}
+
+// GetSwissStandings ranks the entries of a swiss event by score, a win or a
+// bye earning a point, then by Buchholz, the sum of the scores of every
+// opponent played. Entries still level keep their seed order. Only finished
+// matches are counted.
+func (s *service) GetSwissStandings(eventId int) ([]swissStanding, error) {
+	if _, err := s.getEvent(eventId); err != nil {
+		return nil, err
+	}
+	state, err := s.swissState(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := state.ranked(true)
+	standings := make([]swissStanding, 0, len(ranked))
+	for i, id := range ranked {
+		st := *state.Standings[id]
+		st.Position = i + 1
+		standings = append(standings, st)
+	}
+	return standings, nil
+}
+
+func (s *service) swissState(eventId int) (*swissState, error) {
+	entries, err := s.repo.GetEventEntries(eventId)
+	if err != nil {
+		return nil, err
+	}
+	byes, err := s.repo.GetSwissByes(eventId)
+	if err != nil {
+		return nil, err
+	}
+	matches := []db.Match{}
+	if err := s.repo.GetAllMatches(&matches, "", nil, &eventId); err != nil {
+		return nil, err
+	}
+
+	state := &swissState{
+		Finished:   true,
+		Entries:    entries,
+		Standings:  make(map[int]*swissStanding),
+		Opponents:  make(map[int][]int),
+		HadBye:     make(map[int]bool),
+		Rematches:  make(map[[2]int]bool),
+		EntryIndex: make(map[int]int),
+	}
+	for i, entry := range entries {
+		state.Standings[entry.OpponentId] = &swissStanding{OpponentId: entry.OpponentId, Name: entry.Name}
+		state.EntryIndex[entry.OpponentId] = i
+	}
+
+	for _, bye := range byes {
+		if bye.Round > state.Round {
+			state.Round = bye.Round
+		}
+		state.HadBye[bye.OpponentId] = true
+		if st, ok := state.Standings[bye.OpponentId]; ok {
+			st.Byes += 1
+			st.Score += 1
+		}
+	}
+
+	for _, match := range matches {
+		if match.SwissRound == nil {
+			continue
+		}
+		if *match.SwissRound > state.Round {
+			state.Round = *match.SwissRound
+		}
+		if match.Status != string(enums.Past) {
+			state.Finished = false
+		}
+
+		opponents, err := s.opponentsFromMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		a, b := opponents[0], opponents[1]
+		state.Rematches[swissPairKey(a.Id, b.Id)] = true
+		if match.Status != string(enums.Past) {
+			continue
+		}
+
+		state.Opponents[a.Id] = append(state.Opponents[a.Id], b.Id)
+		state.Opponents[b.Id] = append(state.Opponents[b.Id], a.Id)
+		for _, opp := range opponents {
+			st, ok := state.Standings[opp.Id]
+			if !ok {
+				continue
+			}
+			st.Played += 1
+			if opp.IsWinner {
+				st.Won += 1
+				st.Score += 1
+			} else {
+				st.Lost += 1
+			}
+		}
+	}
+
+	for id, st := range state.Standings {
+		for _, opponentId := range state.Opponents[id] {
+			if opp, ok := state.Standings[opponentId]; ok {
+				st.Buchholz += opp.Score
+			}
+		}
+	}
+	return state, nil
+}
+
+// ranked orders the entries by score, then by Buchholz when withBuchholz is
+// set, and then by seed.
+func (state *swissState) ranked(withBuchholz bool) []int {
+	ids := make([]int, 0, len(state.Entries))
+	for _, entry := range state.Entries {
+		ids = append(ids, entry.OpponentId)
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := state.Standings[ids[i]], state.Standings[ids[j]]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if withBuchholz && a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		return state.EntryIndex[ids[i]] < state.EntryIndex[ids[j]]
+	})
+	return ids
+}
+
+// maxSwissRounds is the number of rounds after which every one of count
+// entries has played every other, or had a bye when count is odd.
+func maxSwissRounds(count int) int {
+	if count%2 == 1 {
+		return count
+	}
+	return count - 1
+}
+
+// swissPairer pairs ranked entries without rematches. The sets of entries
+// found not to pair up are remembered, so each is searched only once across
+// every attempt at a round.
+type swissPairer struct {
+	played   map[[2]int]bool
+	unpaired map[string]bool
+}
+
+func newSwissPairer(played map[[2]int]bool) *swissPairer {
+	return &swissPairer{played: played, unpaired: make(map[string]bool)}
+}
+
+// pair pairs the ranked entries top down, each with the highest ranked entry
+// left that it has not played, backtracking when the entries left cannot all
+// be paired.
+func (p *swissPairer) pair(ranked []int) ([][2]int, bool) {
+	if len(ranked) == 0 {
+		return [][2]int{}, true
+	}
+	key := fmt.Sprint(ranked)
+	if p.unpaired[key] {
+		return nil, false
+	}
+
+	first := ranked[0]
+	for i := 1; i < len(ranked); i++ {
+		if p.played[swissPairKey(first, ranked[i])] {
+			continue
+		}
+		rest := make([]int, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:i]...)
+		rest = append(rest, ranked[i+1:]...)
+		if pairs, ok := p.pair(rest); ok {
+			return append([][2]int{{first, ranked[i]}}, pairs...), true
+		}
+	}
+	p.unpaired[key] = true
+	return nil, false
+}
+
+func swissPairKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
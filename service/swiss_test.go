@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestSwissPairerAvoidsRematches(t *testing.T) {
+	played := map[[2]int]bool{
+		swissPairKey(1, 2): true,
+		swissPairKey(3, 4): true,
+	}
+
+	pairs, ok := newSwissPairer(played).pair([]int{1, 2, 3, 4})
+	if !ok {
+		t.Fatal("pair() found no pairing")
+	}
+	want := [][2]int{{1, 3}, {2, 4}}
+	if len(pairs) != len(want) || pairs[0] != want[0] || pairs[1] != want[1] {
+		t.Errorf("pair() = %v, want %v", pairs, want)
+	}
+}
+
+func TestSwissPairerGivesUpWithoutRematchFreePairing(t *testing.T) {
+	// Two groups of seven that have played everyone in the other group can
+	// only pair within their own group, which leaves one over in each.
+	ranked := make([]int, 0, 14)
+	played := make(map[[2]int]bool)
+	for a := 1; a <= 14; a++ {
+		ranked = append(ranked, a)
+		for b := a + 1; b <= 14; b++ {
+			if (a <= 7) != (b <= 7) {
+				played[swissPairKey(a, b)] = true
+			}
+		}
+	}
+
+	pairer := newSwissPairer(played)
+	if _, ok := pairer.pair(ranked); ok {
+		t.Fatal("pair() paired an odd group")
+	}
+	if len(pairer.unpaired) > 1<<14 {
+		t.Errorf("pair() searched %d sets of entries", len(pairer.unpaired))
+	}
+}
+
+func TestMaxSwissRounds(t *testing.T) {
+	for count, want := range map[int]int{2: 1, 3: 3, 4: 3, 7: 7, 8: 7} {
+		if got := maxSwissRounds(count); got != want {
+			t.Errorf("maxSwissRounds(%d) = %d, want %d", count, got, want)
+		}
+	}
+}
+
+func TestAddEventEntriesChecksEntries(t *testing.T) {
+	otherTournament := 2
+	repo := newFakeRepository()
+	repo.tournamentEvents = []db.Event{
+		{Id: 1, TournamentId: 1, Format: string(enums.Singles)},
+		{Id: 2, TournamentId: 1, Format: string(enums.Doubles)},
+	}
+	repo.registered = []db.Player{{Id: 1}, {Id: 2, TournamentId: &otherTournament}}
+	repo.teams = []db.Team{{Id: 5, PlayerAId: 1, PlayerBId: 3}}
+	svc := &service{repo: repo}
+
+	tests := []struct {
+		eventId    int
+		opponentId int
+		want       error
+	}{
+		{1, 1, nil},
+		{1, 2, ErrOpponentNotInTournament},
+		{1, 9, ErrPlayerNotFound},
+		{1, 5, ErrPlayerNotFound},
+		{2, 5, nil},
+		{2, 1, ErrTeamNotFound},
+	}
+	for _, tt := range tests {
+		err := svc.AddEventEntries(tt.eventId, []BracketEntry{{OpponentId: tt.opponentId}})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("AddEventEntries(%d, %d) error = %v, want %v", tt.eventId, tt.opponentId, err, tt.want)
+		}
+	}
+	if len(repo.entries) != 2 {
+		t.Errorf("%d entries added, want 2", len(repo.entries))
+	}
+}
@@ -1,9 +1,43 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/adarsh-a-tw/tt-backend/db"
 )
 
-func (s *service) CreateTeam(playerAName, playerBName string) error {
-	return s.repo.CreateTeam(&db.Team{PlayerA: playerAName, PlayerB: playerBName})
+var ErrSamePlayerTwice = errors.New("a team needs two different players")
+var ErrTeamNotFound = errors.New("team not found")
+
+// CreateTeam registers a doubles pair of registered players, for the given
+// tournament when tournamentId is set. Players registered for a tournament
+// can only pair up for that tournament.
+func (s *service) CreateTeam(playerAId, playerBId int, tournamentId *int) error {
+	if tournamentId != nil {
+		if _, err := s.getTournament(*tournamentId); err != nil {
+			return err
+		}
+	}
+	if playerAId == playerBId {
+		return ErrSamePlayerTwice
+	}
+	for _, playerId := range []int{playerAId, playerBId} {
+		player, err := s.getPlayer(playerId)
+		if err != nil {
+			return err
+		}
+		if player.TournamentId != nil && (tournamentId == nil || *player.TournamentId != *tournamentId) {
+			return ErrOpponentNotInTournament
+		}
+	}
+	return s.repo.CreateTeam(&db.Team{PlayerAId: playerAId, PlayerBId: playerBId, TournamentId: tournamentId})
+}
+
+func (s *service) getTeam(id int) (*db.Team, error) {
+	team, err := s.repo.GetTeamById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTeamNotFound
+	}
+	return team, err
 }
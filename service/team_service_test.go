@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+)
+
+// newTeamFixture registers players 1 and 2 for tournament 1, player 3 for no
+// tournament and player 4 for tournament 2.
+func newTeamFixture() (*fakeRepository, *service) {
+	cityOpen, clubCup := 1, 2
+	repo := newFakeRepository()
+	repo.tournaments = []db.Tournament{{Id: cityOpen, Name: "City Open"}, {Id: clubCup, Name: "Club Cup"}}
+	repo.registered = []db.Player{
+		{Id: 1, Name: "Asha", TournamentId: &cityOpen},
+		{Id: 2, Name: "Bala", TournamentId: &cityOpen},
+		{Id: 3, Name: "Asha"},
+		{Id: 4, Name: "Chen", TournamentId: &clubCup},
+	}
+	repo.lastId = 4
+	return repo, &service{repo: repo}
+}
+
+func TestCreateTeam(t *testing.T) {
+	cityOpen, missingTournament := 1, 9
+
+	tests := []struct {
+		name         string
+		playerAId    int
+		playerBId    int
+		tournamentId *int
+		wantErr      error
+	}{
+		{"players of the tournament", 1, 2, &cityOpen, nil},
+		{"unscoped player in a tournament", 1, 3, &cityOpen, nil},
+		{"same player twice", 3, 3, nil, ErrSamePlayerTwice},
+		{"player of another tournament", 1, 4, &cityOpen, ErrOpponentNotInTournament},
+		{"tournament player without a tournament", 1, 3, nil, ErrOpponentNotInTournament},
+		{"unknown player", 1, 7, &cityOpen, ErrPlayerNotFound},
+		{"unknown tournament", 1, 2, &missingTournament, ErrTournamentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, svc := newTeamFixture()
+
+			err := svc.CreateTeam(tt.playerAId, tt.playerBId, tt.tournamentId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateTeam() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.teams) != 0 {
+					t.Errorf("%d teams created by a rejected pair", len(repo.teams))
+				}
+				return
+			}
+			if len(repo.teams) != 1 {
+				t.Fatalf("%d teams created, want 1", len(repo.teams))
+			}
+			if team := repo.teams[0]; team.PlayerAId != tt.playerAId || team.PlayerBId != tt.playerBId {
+				t.Errorf("team pairs players %d and %d, want %d and %d", team.PlayerAId, team.PlayerBId, tt.playerAId, tt.playerBId)
+			}
+		})
+	}
+}
+
+func TestFindPlayerId(t *testing.T) {
+	cityOpen, clubCup := 1, 2
+
+	tests := []struct {
+		name         string
+		playerName   string
+		tournamentId *int
+		want         int
+		wantErr      error
+	}{
+		{"tournament player preferred", "Asha", &cityOpen, 1, nil},
+		{"unscoped player without a tournament", "Asha", nil, 3, nil},
+		{"unscoped player in another tournament", "Asha", &clubCup, 3, nil},
+		{"player of another tournament", "Chen", &cityOpen, 0, ErrPlayerNotFound},
+		{"unknown name", "Dev", &cityOpen, 0, ErrPlayerNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, svc := newTeamFixture()
+
+			got, err := svc.FindPlayerId(tt.playerName, tt.tournamentId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FindPlayerId() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FindPlayerId() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPlayerIdRejectsAmbiguousName(t *testing.T) {
+	repo, svc := newTeamFixture()
+	repo.registered = append(repo.registered, db.Player{Id: 5, Name: "Asha"})
+
+	if _, err := svc.FindPlayerId("Asha", nil); !errors.Is(err, ErrAmbiguousPlayerName) {
+		t.Errorf("FindPlayerId() error = %v, want %v", err, ErrAmbiguousPlayerName)
+	}
+	if got, err := svc.FindPlayerId("Asha", &repo.tournaments[0].Id); err != nil || got != 1 {
+		t.Errorf("FindPlayerId() in the tournament = %d, %v, want 1", got, err)
+	}
+}
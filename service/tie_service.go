@@ -0,0 +1,274 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrTieNotFound = errors.New("tie not found")
+var ErrInvalidTieOrder = errors.New("tie order must name a lineup position for every rubber")
+var ErrTeamEventNeedsTies = errors.New("matches of a team event are played as rubbers of a tie")
+
+// DoublesRubber marks a doubles rubber in the order of a tie.
+const DoublesRubber = "D"
+
+type tieSummary struct {
+	Id           int
+	EventId      *int
+	SideA        string
+	SideB        string
+	RubbersToWin int
+	ScoreA       int
+	ScoreB       int
+	Status       enums.MatchStatus
+	WinnerIsA    *bool
+}
+
+type tieDetail struct {
+	tieSummary
+	Rubbers []matchInfo
+}
+
+// CreateTie creates a team tie between two sides and a rubber for each entry
+// of the order. A singles rubber such as "A-X" is played between the players
+// nominated as A in lineupA and X in lineupB, and a doubles rubber "D"
+// between the pairs nominated as D in each lineup. The first side to win more
+// than half of the rubbers wins the tie. Every rubber is played at the stage
+// of the tie.
+func (s *service) CreateTie(
+	eventId *int,
+	stage enums.MatchStage,
+	sideA string,
+	sideB string,
+	order []string,
+	lineupA map[string]int,
+	lineupB map[string]int,
+	settings MatchSettings,
+) (int, error) {
+	var tieId int
+	err := s.inTx(func(tx *service) error {
+		id, err := tx.createTie(eventId, stage, sideA, sideB, order, lineupA, lineupB, settings)
+		tieId = id
+		return err
+	})
+	return tieId, err
+}
+
+func (s *service) createTie(
+	eventId *int,
+	stage enums.MatchStage,
+	sideA string,
+	sideB string,
+	order []string,
+	lineupA map[string]int,
+	lineupB map[string]int,
+	settings MatchSettings,
+) (int, error) {
+	if len(order) == 0 || len(order)%2 == 0 {
+		return 0, ErrInvalidTieOrder
+	}
+	if eventId != nil {
+		event, err := s.getEvent(*eventId)
+		if err != nil {
+			return 0, err
+		}
+		if event.Format != string(enums.Team) {
+			return 0, ErrEventFormatMismatch
+		}
+	}
+
+	type rubber struct {
+		format enums.MatchFormat
+		oppA   int
+		oppB   int
+	}
+	rubbers := make([]rubber, 0, len(order))
+	for _, entry := range order {
+		format, keyA, keyB := enums.Singles, entry, entry
+		if entry == DoublesRubber {
+			format = enums.Doubles
+		} else {
+			keys := strings.Split(entry, "-")
+			if len(keys) != 2 {
+				return 0, ErrInvalidTieOrder
+			}
+			keyA, keyB = keys[0], keys[1]
+		}
+		oppA, okA := lineupA[keyA]
+		oppB, okB := lineupB[keyB]
+		if !okA || !okB {
+			return 0, ErrInvalidTieOrder
+		}
+		rubbers = append(rubbers, rubber{format, oppA, oppB})
+	}
+
+	id, err := s.repo.CreateTie(&db.Tie{
+		EventId:      eventId,
+		SideA:        sideA,
+		SideB:        sideB,
+		RubbersToWin: len(order)/2 + 1,
+		Status:       string(enums.Upcoming),
+	})
+	if err != nil {
+		return 0, err
+	}
+	tieId := int(id)
+
+	settings.EventId = eventId
+	for i, r := range rubbers {
+		match, err := s.newMatch(r.format, stage, settings)
+		if err != nil {
+			return 0, err
+		}
+		rubberNumber := i + 1
+		match.TieId = &tieId
+		match.RubberNumber = &rubberNumber
+		matchId, err := s.repo.CreateMatch(match)
+		if err != nil {
+			return 0, err
+		}
+		match.Id = int(matchId)
+
+		if err := s.addOpponentToMatch(*match, r.oppA, true); err != nil {
+			return 0, err
+		}
+		if err := s.addOpponentToMatch(*match, r.oppB, false); err != nil {
+			return 0, err
+		}
+		if err := s.checkOpponentsInEvent(match.Id, r.format, eventId); err != nil {
+			return 0, err
+		}
+	}
+
+	return tieId, nil
+}
+
+func (s *service) GetTie(tieId int) (*tieDetail, error) {
+	tie, err := s.getTie(tieId)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.repo.GetMatchesByTieId(tieId)
+	if err != nil {
+		return nil, err
+	}
+
+	rubbers := make([]matchInfo, 0, len(matches))
+	for _, match := range matches {
+		info, err := s.newMatchInfo(match)
+		if err != nil {
+			return nil, err
+		}
+		rubbers = append(rubbers, *info)
+	}
+
+	return &tieDetail{tieSummary: newTieSummary(*tie), Rubbers: rubbers}, nil
+}
+
+func (s *service) getTie(id int) (*db.Tie, error) {
+	tie, err := s.repo.GetTieById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTieNotFound
+	}
+	return tie, err
+}
+
+// tieOfMatch returns the tie the match is a rubber of, or nil.
+func (s *service) tieOfMatch(match db.Match) (*tieSummary, error) {
+	if match.TieId == nil {
+		return nil, nil
+	}
+	tie, err := s.getTie(*match.TieId)
+	if err != nil {
+		return nil, err
+	}
+	summary := newTieSummary(*tie)
+	return &summary, nil
+}
+
+// refreshTie rolls the results of the rubbers up into the score of the tie
+// the match belongs to. Once a side has won the tie the rubbers that are not
+// finished are closed as unplayed, keeping the score of any that had started,
+// and they are opened again if a result is undone and the tie is no longer
+// decided. The rubbers closed or opened are reported as changed.
+func (s *service) refreshTie(match *db.Match) error {
+	if match.TieId == nil {
+		return nil
+	}
+	tie, err := s.repo.LockTieById(*match.TieId)
+	if err != nil {
+		return err
+	}
+	rubbers, err := s.repo.GetMatchesByTieId(tie.Id)
+	if err != nil {
+		return err
+	}
+
+	tie.ScoreA, tie.ScoreB = 0, 0
+	started := false
+	for _, rubber := range rubbers {
+		if rubber.Status != string(enums.Upcoming) && rubber.Result != string(enums.Unplayed) {
+			started = true
+		}
+		if rubber.Status != string(enums.Past) || rubber.Result == string(enums.Unplayed) {
+			continue
+		}
+		opponents, err := s.opponentsFromMatch(rubber)
+		if err != nil {
+			return err
+		}
+		if opponents[0].IsWinner {
+			tie.ScoreA += 1
+		} else if opponents[1].IsWinner {
+			tie.ScoreB += 1
+		}
+	}
+
+	decided := tie.ScoreA >= tie.RubbersToWin || tie.ScoreB >= tie.RubbersToWin
+	switch {
+	case decided:
+		winnerIsA := tie.ScoreA >= tie.RubbersToWin
+		tie.WinnerIsA = &winnerIsA
+		tie.Status = string(enums.Past)
+	case started:
+		tie.WinnerIsA = nil
+		tie.Status = string(enums.Ongoing)
+	default:
+		tie.WinnerIsA = nil
+		tie.Status = string(enums.Upcoming)
+	}
+
+	for _, rubber := range rubbers {
+		if decided && rubber.Status != string(enums.Past) {
+			err = s.closeUnplayed(rubber.Id)
+		} else if !decided && rubber.Result == string(enums.Unplayed) {
+			err = s.reopenUnplayed(rubber.Id)
+		} else {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.markChanged(rubber.Id)
+	}
+
+	return s.repo.UpdateTie(tie)
+}
+
+func newTieSummary(tie db.Tie) tieSummary {
+	return tieSummary{
+		Id:           tie.Id,
+		EventId:      tie.EventId,
+		SideA:        tie.SideA,
+		SideB:        tie.SideB,
+		RubbersToWin: tie.RubbersToWin,
+		ScoreA:       tie.ScoreA,
+		ScoreB:       tie.ScoreB,
+		Status:       enums.MatchStatus(tie.Status),
+		WinnerIsA:    tie.WinnerIsA,
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestRefreshTieReportsClosedAndOpenedRubbers(t *testing.T) {
+	tieId := 1
+	repo := newFakeRepository()
+	repo.ties = []db.Tie{{Id: tieId, SideA: "Home", SideB: "Away", RubbersToWin: 2, Status: string(enums.Upcoming)}}
+	for rubberNumber := 1; rubberNumber <= 3; rubberNumber++ {
+		number := rubberNumber
+		id := repo.nextId()
+		repo.matches = append(repo.matches, db.Match{
+			Id:           id,
+			Format:       string(enums.Singles),
+			Status:       string(enums.Upcoming),
+			TieId:        &tieId,
+			RubberNumber: &number,
+		})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: 10 + rubberNumber, IsOpponentA: true})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: 20 + rubberNumber, IsOpponentA: false})
+	}
+	svc := &service{repo: repo}
+
+	if _, err := svc.EndMatch(1, enums.Walkover, true); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := svc.EndMatch(2, enums.Walkover, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("EndMatch() changed = %v, want %v", changed, want)
+	}
+	if rubber := repo.findMatch(3); rubber.Status != string(enums.Past) || rubber.Result != string(enums.Unplayed) {
+		t.Errorf("third rubber is %s %s, want closed as unplayed", rubber.Status, rubber.Result)
+	}
+	if tie := repo.ties[0]; tie.Status != string(enums.Past) || tie.ScoreA != 2 {
+		t.Errorf("tie is %s at %d-%d, want won by side A", tie.Status, tie.ScoreA, tie.ScoreB)
+	}
+
+	changed, err = svc.inTxChanging(func(tx *service) error {
+		match, err := tx.repo.LockMatchById(2)
+		if err != nil {
+			return err
+		}
+		return tx.reopenMatch(match)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("reopenMatch() changed = %v, want %v", changed, want)
+	}
+	if rubber := repo.findMatch(3); rubber.Status != string(enums.Upcoming) || rubber.Result != "" {
+		t.Errorf("third rubber is %s %s, want open again", rubber.Status, rubber.Result)
+	}
+}
+
+func TestDecidedTieClosesOngoingRubber(t *testing.T) {
+	tieId := 1
+	repo := newFakeRepository()
+	repo.ties = []db.Tie{{Id: tieId, SideA: "Home", SideB: "Away", RubbersToWin: 2, Status: string(enums.Upcoming)}}
+	for rubberNumber := 1; rubberNumber <= 3; rubberNumber++ {
+		number := rubberNumber
+		id := repo.nextId()
+		repo.matches = append(repo.matches, db.Match{
+			Id:           id,
+			Format:       string(enums.Singles),
+			GamePoint:    11,
+			SetCount:     5,
+			Status:       string(enums.Upcoming),
+			ScoringRules: string(enums.StandardRules),
+			TieId:        &tieId,
+			RubberNumber: &number,
+		})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: 10 + rubberNumber, IsOpponentA: true})
+		repo.AddPlayerToMatch(&db.PlayerMatchMapping{MatchId: id, PlayerId: 20 + rubberNumber, IsOpponentA: false})
+	}
+	svc := &service{repo: repo}
+
+	if err := svc.CreateSet(3, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateScore(3, repo.sets[0].Id, true, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.EndMatch(1, enums.Walkover, true); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := svc.EndMatch(2, enums.Walkover, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("EndMatch() changed = %v, want %v", changed, want)
+	}
+	if rubber := repo.findMatch(3); rubber.Status != string(enums.Past) || rubber.Result != string(enums.Unplayed) {
+		t.Errorf("ongoing rubber is %s %s, want closed as unplayed", rubber.Status, rubber.Result)
+	}
+	if set := repo.sets[0]; set.OpponentAScore != 1 {
+		t.Errorf("dead rubber score = %d-%d, want 1-0 kept", set.OpponentAScore, set.OpponentBScore)
+	}
+	if _, err := svc.UpdateScore(3, repo.sets[0].Id, true, false, nil); !errors.Is(err, ErrGameOverOrSetCountExceeded) {
+		t.Errorf("UpdateScore() on the dead rubber error = %v, want %v", err, ErrGameOverOrSetCountExceeded)
+	}
+
+	if _, err := svc.Undo(2); err != nil {
+		t.Fatal(err)
+	}
+	if rubber := repo.findMatch(3); rubber.Status != string(enums.Ongoing) || rubber.Result != "" {
+		t.Errorf("rubber is %s %s after undo, want ongoing again", rubber.Status, rubber.Result)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+var ErrTournamentNotFound = errors.New("tournament not found")
+var ErrTournamentExists = errors.New("tournament already exists")
+var ErrEventNotFound = errors.New("event not found")
+var ErrEventFormatMismatch = errors.New("match format does not match the event format")
+var ErrOpponentNotInTournament = errors.New("opponent is not registered in the tournament")
+
+type tournament struct {
+	Id   int
+	Name string
+}
+
+type event struct {
+	Id           int
+	TournamentId int
+	Name         string
+	Format       enums.MatchFormat
+}
+
+func (s *service) CreateTournament(name string) (int, error) {
+	id, err := s.repo.CreateTournament(&db.Tournament{Name: name})
+	if errors.Is(err, db.ErrUniqueViolation) {
+		return 0, ErrTournamentExists
+	}
+	return int(id), err
+}
+
+func (s *service) GetTournaments() ([]tournament, error) {
+	tournamentsFromDb, err := s.repo.GetAllTournaments()
+	if err != nil {
+		return nil, err
+	}
+
+	tournaments := make([]tournament, 0, len(tournamentsFromDb))
+	for _, t := range tournamentsFromDb {
+		tournaments = append(tournaments, tournament{Id: t.Id, Name: t.Name})
+	}
+	return tournaments, nil
+}
+
+// CreateEvent adds an event such as Men's Singles to the tournament. Every
+// match of the event is played in its format.
+func (s *service) CreateEvent(tournamentId int, name string, format enums.MatchFormat) (int, error) {
+	if _, err := s.getTournament(tournamentId); err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.CreateEvent(&db.Event{TournamentId: tournamentId, Name: name, Format: string(format)})
+	return int(id), err
+}
+
+func (s *service) GetEvents(tournamentId int) ([]event, error) {
+	if _, err := s.getTournament(tournamentId); err != nil {
+		return nil, err
+	}
+
+	eventsFromDb, err := s.repo.GetEventsByTournamentId(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]event, 0, len(eventsFromDb))
+	for _, e := range eventsFromDb {
+		events = append(events, newEvent(e))
+	}
+	return events, nil
+}
+
+func (s *service) getTournament(id int) (*db.Tournament, error) {
+	tournament, err := s.repo.GetTournamentById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, err
+}
+
+func (s *service) getEvent(id int) (*db.Event, error) {
+	event, err := s.repo.GetEventById(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+	return event, err
+}
+
+// tournamentIdOfMatch returns the tournament the event of the match belongs
+// to, or nil when the match is not part of an event.
+func (s *service) tournamentIdOfMatch(match db.Match) (*int, error) {
+	if match.EventId == nil {
+		return nil, nil
+	}
+	event, err := s.getEvent(*match.EventId)
+	if err != nil {
+		return nil, err
+	}
+	return &event.TournamentId, nil
+}
+
+// checkOpponentsInEvent rejects a match of an event between opponents
+// registered for another tournament. Opponents that are not scoped to any
+// tournament can play in every event.
+func (s *service) checkOpponentsInEvent(matchId int, format enums.MatchFormat, eventId *int) error {
+	if eventId == nil {
+		return nil
+	}
+	event, err := s.getEvent(*eventId)
+	if err != nil {
+		return err
+	}
+
+	tournamentIds := make([]*int, 0)
+	if format == enums.Doubles {
+		rows, err := s.repo.GetTeamInfoByMatchId(matchId)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			tournamentIds = append(tournamentIds, row.TournamentId)
+		}
+	} else {
+		rows, err := s.repo.GetPlayerInfoByMatchId(matchId)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			tournamentIds = append(tournamentIds, row.TournamentId)
+		}
+	}
+
+	for _, tournamentId := range tournamentIds {
+		if tournamentId != nil && *tournamentId != event.TournamentId {
+			return ErrOpponentNotInTournament
+		}
+	}
+	return nil
+}
+
+func newEvent(e db.Event) event {
+	return event{
+		Id:           e.Id,
+		TournamentId: e.TournamentId,
+		Name:         e.Name,
+		Format:       enums.MatchFormat(e.Format),
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adarsh-a-tw/tt-backend/db"
+	"github.com/adarsh-a-tw/tt-backend/enums"
+)
+
+func TestCreateTournamentRejectsDuplicateName(t *testing.T) {
+	svc := &service{repo: newFakeRepository()}
+	if _, err := svc.CreateTournament("City Open"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateTournament("City Open"); !errors.Is(err, ErrTournamentExists) {
+		t.Errorf("CreateTournament() error = %v, want %v", err, ErrTournamentExists)
+	}
+}
+
+func TestCreateMatchInEvent(t *testing.T) {
+	eventId, missingEventId := 1, 9
+	otherTournament := 2
+
+	tests := []struct {
+		name     string
+		format   enums.MatchFormat
+		eventId  *int
+		playerB  int
+		wantErr  error
+		wantRows int
+	}{
+		{"players of the tournament", enums.Singles, &eventId, 2, nil, 1},
+		{"unscoped player", enums.Singles, &eventId, 3, nil, 1},
+		{"player of another tournament", enums.Singles, &eventId, 4, ErrOpponentNotInTournament, 0},
+		{"unknown event", enums.Singles, &missingEventId, 2, ErrEventNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournamentId := 1
+			repo := newFakeRepository()
+			repo.tournamentEvents = []db.Event{{Id: eventId, TournamentId: tournamentId, Format: string(enums.Singles)}}
+			repo.registered = []db.Player{
+				{Id: 1, TournamentId: &tournamentId},
+				{Id: 2, TournamentId: &tournamentId},
+				{Id: 3},
+				{Id: 4, TournamentId: &otherTournament},
+			}
+			svc := &service{repo: repo}
+
+			err := svc.CreateSinglesMatch(enums.Prelims, 1, tt.playerB, MatchSettings{EventId: tt.eventId, MaxSets: 5, GamePoint: 11})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateSinglesMatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.matches) != tt.wantRows || len(repo.players) != 2*tt.wantRows {
+				t.Errorf("%d matches with %d players, want %d", len(repo.matches), len(repo.players), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestCreateMatchInEventOfAnotherFormat(t *testing.T) {
+	eventId := 1
+	repo := newFakeRepository()
+	repo.tournamentEvents = []db.Event{{Id: eventId, TournamentId: 1, Format: string(enums.Doubles)}}
+	svc := &service{repo: repo}
+
+	err := svc.CreateSinglesMatch(enums.Prelims, 1, 2, MatchSettings{EventId: &eventId, MaxSets: 5, GamePoint: 11})
+	if !errors.Is(err, ErrEventFormatMismatch) {
+		t.Errorf("CreateSinglesMatch() error = %v, want %v", err, ErrEventFormatMismatch)
+	}
+}